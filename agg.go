@@ -0,0 +1,716 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Walther-Knight/blogGATOR/internal/database"
+	"github.com/Walther-Knight/blogGATOR/internal/htmltext"
+	"github.com/Walther-Knight/blogGATOR/internal/rss"
+	"github.com/Walther-Knight/blogGATOR/internal/urlnorm"
+)
+
+// feedLeaseMargin is how long a claimed feed stays reserved for one worker
+// beyond the HTTP timeout, to store what was fetched. If the worker's process
+// dies mid-fetch the lease lapses and any aggregator sharing the database may
+// claim the feed again.
+const feedLeaseMargin = 5 * time.Minute
+
+// Failing feeds are retried after feedBackoffBase, doubling with each
+// consecutive failure up to feedBackoffMax, and are disabled once they have
+// been failing for feedDisableAfter.
+const (
+	feedBackoffBase  = 5 * time.Minute
+	feedBackoffMax   = 24 * time.Hour
+	feedDisableAfter = 7 * 24 * time.Hour
+)
+
+// postBatchSize is the most items written by one multi-row upsert.
+const postBatchSize = 500
+
+// postTextWidth is the column plain-text descriptions are wrapped at.
+const postTextWidth = 78
+
+// adaptiveMaxInterval caps how far adaptive scheduling may space out fetches
+// of a feed that rarely posts.
+const adaptiveMaxInterval = 7 * 24 * time.Hour
+
+func handlerAgg(s *state, cmd command) error {
+	once := false
+	var args []string
+	for _, arg := range cmd.args {
+		if arg == "--once" {
+			once = true
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf(("invalid command: syntax agg [--once] <timeBetweenReqs> [concurrency]"))
+	}
+
+	timeBetweenReqs, err := time.ParseDuration(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid command: duration should be valid: %w", err)
+	}
+
+	concurrency := 1
+	if len(args) == 2 {
+		concurrency, err = strconv.Atoi(args[1])
+		if err != nil || concurrency < 1 {
+			return fmt.Errorf("invalid command: concurrency should be a positive integer")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// On the first signal, restore default signal handling so a second
+	// interrupt exits at once while in-progress fetches finish.
+	stopShutdown := context.AfterFunc(ctx, func() {
+		stop()
+		fmt.Println("Shutting down, waiting for in-progress fetches")
+	})
+	defer stopShutdown()
+
+	if once {
+		return claimDueFeeds(ctx, s, concurrency, timeBetweenReqs)
+	}
+	fmt.Printf("Collecting feeds every %v with %d workers\n", timeBetweenReqs, concurrency)
+	return aggLoop(ctx, s, concurrency, timeBetweenReqs)
+}
+
+// aggLoop refreshes due feeds every interval until ctx is cancelled.
+func aggLoop(ctx context.Context, s *state, concurrency int, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := claimDueFeeds(ctx, s, concurrency, interval); err != nil {
+			fmt.Printf("error claiming feeds: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimDueFeeds refreshes every feed whose next_fetch_at has passed with
+// concurrency workers, returning once they are all done. Each worker claims
+// its next feed only when it is free to fetch it, taking a lease on the row
+// with FOR UPDATE SKIP LOCKED, so neither workers nor other agg processes get
+// the same feed. The lease lasts the HTTP timeout plus feedLeaseMargin.
+// Leases and due times are read from the database clock, so aggregators on
+// hosts with skewed clocks or different time zones agree on them. Only feeds
+// due when the pass started are claimed, so a feed that falls due again while
+// the pass runs, or whose backoff ends during it, waits for the next one.
+// Once ctx is cancelled no more feeds are claimed, but a fetch that has
+// started is allowed to finish storing its posts.
+func claimDueFeeds(ctx context.Context, s *state, concurrency int, defaultInterval time.Duration) error {
+	passStart, err := s.db.GetDatabaseTime(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("error reading database time: %w", err)
+	}
+	params := database.ClaimNextFeedParams{
+		LeaseSeconds: durationSeconds(s.fetcher.Timeout() + feedLeaseMargin),
+		DueBefore:    passStart,
+	}
+
+	errs := make(chan error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- aggWorker(ctx, s, params, defaultInterval)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggWorker claims and refreshes due feeds one at a time until none are left
+// or ctx is cancelled.
+func aggWorker(ctx context.Context, s *state, params database.ClaimNextFeedParams, defaultInterval time.Duration) error {
+	for {
+		feed, err := s.db.ClaimNextFeed(ctx, params)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error fetching next feed: %w", err)
+		}
+		// On error the failure is recorded and the feed backs off like one
+		// that could not be fetched.
+		if err := refreshFeed(context.WithoutCancel(ctx), s, feed, defaultInterval); err != nil {
+			fmt.Printf("error storing %s: %v\n", feed.Url, err)
+		}
+	}
+}
+
+// fetchStats summarises one fetch attempt for the feed_fetch_log.
+type fetchStats struct {
+	statusCode int
+	bytes      int
+	itemCount  int
+	newPosts   int
+	// skippedItems counts malformed items dropped by lenient parsing.
+	skippedItems int
+	// notBefore is the server's earliest requested refetch time, if any.
+	notBefore time.Time
+	// schedule is the publisher schedule from a freshly parsed feed. It is
+	// nil when the body was not parsed, e.g. on a 304.
+	schedule *rss.Schedule
+}
+
+// refreshFeed fetches a feed and stores the outcome in a single transaction:
+// new posts, cache headers, publisher schedule, the fetch log entry and the
+// feed's next fetch time are committed together or not at all. If that
+// transaction fails, the attempt and the failure are recorded on their own so
+// that a feed which can be fetched but not stored still shows up as failing.
+func refreshFeed(ctx context.Context, s *state, feed database.ClaimNextFeedRow, defaultInterval time.Duration) error {
+	start := time.Now()
+	fetchRes, stats, fetchErr := fetchFeed(ctx, s.fetcher, feed)
+	if fetchErr != nil {
+		fmt.Printf("error scraping %s: %v\n", feed.Url, fetchErr)
+	}
+
+	err := storeRefresh(ctx, s, feed, fetchRes, stats, start, fetchErr, defaultInterval)
+	if err == nil {
+		return nil
+	}
+
+	// The posts were rolled back, so none of them count as new.
+	stats.newPosts = 0
+	// Once the failure has set a backoff the lease can go; otherwise it is
+	// left to expire so the feed is not claimed again straight away.
+	if err2 := recordFetch(ctx, s.db, feed.ID, start, stats, err); err2 != nil {
+		fmt.Printf("error recording failed refresh of %s: %v\n", feed.Url, err2)
+	} else if err3 := s.db.ReleaseFeed(ctx, feed.ID); err3 != nil {
+		fmt.Printf("error releasing feed: %v\n", err3)
+	}
+	return err
+}
+
+// storeRefresh writes the outcome of one fetch in a transaction.
+func storeRefresh(ctx context.Context, s *state, feed database.ClaimNextFeedRow, fetchRes *rss.FetchResult, stats fetchStats, start time.Time, fetchErr error, defaultInterval time.Duration) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.db.WithTx(tx)
+
+	if fetchErr == nil && !fetchRes.NotModified {
+		if err := storeFeed(ctx, qtx, feed, fetchRes, &stats); err != nil {
+			return err
+		}
+	}
+
+	if fetchErr == nil && fetchRes.MovedTo != "" {
+		if err := moveFeed(ctx, qtx, feed, fetchRes.MovedTo); err != nil {
+			return err
+		}
+	}
+
+	if err := recordFetch(ctx, qtx, feed.ID, start, stats, fetchErr); err != nil {
+		return err
+	}
+
+	// Marking the feed fetched also releases its lease. The next fetch is
+	// stored relative to the database clock, like the lease.
+	fetchedAt := time.Now()
+	nextFetch := nextFetchAt(ctx, qtx, feed, stats, fetchedAt, defaultInterval)
+	err2 := qtx.MarkFeed(ctx, database.MarkFeedParams{
+		LastFetchedAt: sql.NullTime{
+			Time:  fetchedAt,
+			Valid: true,
+		},
+		NextFetchSeconds: durationSeconds(nextFetch.Sub(fetchedAt)),
+		ID:               feed.ID,
+	})
+	if err2 != nil {
+		return fmt.Errorf("error marking feed fetched: %w", err2)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing feed refresh: %w", err)
+	}
+	return nil
+}
+
+// moveFeed points a feed at the URL it permanently redirected to, keeping the
+// old URL in feed_aliases so it can still be followed. A feed that moved to
+// the URL of another feed is left alone for dedupefeeds to resolve.
+func moveFeed(ctx context.Context, q *database.Queries, feed database.ClaimNextFeedRow, movedTo string) error {
+	newURL := movedTo
+	if newURL == feed.Url {
+		return nil
+	}
+
+	// A move between forms of the same URL, such as to https, finds the feed
+	// itself.
+	if other, err := findFeed(ctx, q, newURL); err == nil && other.ID != feed.ID {
+		fmt.Printf("feed %s moved to %s, which is already feed %s\n", feed.Url, newURL, other.Name)
+		return nil
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error checking moved feed url: %w", err)
+	}
+
+	err2 := q.CreateFeedAlias(ctx, database.CreateFeedAliasParams{
+		Url:       feed.Url,
+		FeedID:    feed.ID,
+		CreatedAt: time.Now(),
+		UrlKey:    urlKey(feed.Url),
+	})
+	if err2 != nil {
+		return fmt.Errorf("error saving feed alias: %w", err2)
+	}
+	// The feed may be moving back to a URL it used before.
+	if err := q.DeleteFeedAlias(ctx, newURL); err != nil {
+		return fmt.Errorf("error removing feed alias: %w", err)
+	}
+
+	err3 := q.SetFeedURL(ctx, database.SetFeedURLParams{
+		ID:        feed.ID,
+		Url:       newURL,
+		UrlKey:    urlKey(newURL),
+		UpdatedAt: time.Now(),
+	})
+	if err3 != nil {
+		return fmt.Errorf("error updating moved feed url: %w", err3)
+	}
+
+	fmt.Printf("feed %s moved permanently to %s\n", feed.Url, newURL)
+	return nil
+}
+
+// nextFetchAt schedules the feed's next fetch after our own interval, but
+// never earlier than the publisher's ttl or the server's Cache-Control,
+// Expires or Retry-After hints, and never inside its skipHours or skipDays.
+func nextFetchAt(ctx context.Context, q *database.Queries, feed database.ClaimNextFeedRow, stats fetchStats, fetchedAt time.Time, defaultInterval time.Duration) time.Time {
+	next := fetchedAt.Add(fetchInterval(ctx, q, feed, defaultInterval))
+	if stats.notBefore.After(next) {
+		next = stats.notBefore
+	}
+
+	schedule := storedSchedule(feed)
+	if stats.schedule != nil {
+		schedule = *stats.schedule
+	}
+	if ttl := fetchedAt.Add(time.Duration(schedule.TTLMinutes) * time.Minute); ttl.After(next) {
+		next = ttl
+	}
+	return schedule.NextAllowed(next)
+}
+
+// storedSchedule rebuilds the publisher schedule saved by the last full
+// fetch, for responses such as 304 that carry no feed body.
+func storedSchedule(feed database.ClaimNextFeedRow) rss.Schedule {
+	var schedule rss.Schedule
+	if feed.TtlMinutes.Valid {
+		schedule.TTLMinutes = int(feed.TtlMinutes.Int32)
+	}
+	for _, value := range strings.Split(feed.SkipHours, ",") {
+		if hour, err := strconv.Atoi(value); err == nil {
+			schedule.SkipHours = append(schedule.SkipHours, hour)
+		}
+	}
+	for _, value := range strings.Split(feed.SkipDays, ",") {
+		if day, err := strconv.Atoi(value); err == nil {
+			schedule.SkipDays = append(schedule.SkipDays, time.Weekday(day))
+		}
+	}
+	return schedule
+}
+
+func savePublisherSchedule(ctx context.Context, q *database.Queries, feedID int32, schedule rss.Schedule) error {
+	var skipHours, skipDays []string
+	for _, hour := range schedule.SkipHours {
+		skipHours = append(skipHours, strconv.Itoa(hour))
+	}
+	for _, day := range schedule.SkipDays {
+		skipDays = append(skipDays, strconv.Itoa(int(day)))
+	}
+
+	return q.SetFeedPublisherSchedule(ctx, database.SetFeedPublisherScheduleParams{
+		TtlMinutes: sql.NullInt32{
+			Int32: int32(schedule.TTLMinutes),
+			Valid: schedule.TTLMinutes > 0,
+		},
+		SkipHours: strings.Join(skipHours, ","),
+		SkipDays:  strings.Join(skipDays, ","),
+		ID:        feedID,
+	})
+}
+
+// fetchInterval decides how long to wait before fetching the feed again. The
+// feed's own interval, or the agg interval when it has none, is a minimum;
+// adaptive feeds poll twice per average gap between their recent posts, up
+// to adaptiveMaxInterval.
+func fetchInterval(ctx context.Context, q *database.Queries, feed database.ClaimNextFeedRow, defaultInterval time.Duration) time.Duration {
+	interval := defaultInterval
+	if feed.FetchIntervalSeconds.Valid {
+		interval = time.Duration(feed.FetchIntervalSeconds.Int32) * time.Second
+	}
+	if !feed.AdaptiveSchedule {
+		return interval
+	}
+
+	averageGap, err := q.GetFeedPostingInterval(ctx, feed.ID)
+	if err != nil {
+		fmt.Printf("error estimating posting interval of %s: %v\n", feed.Url, err)
+		return interval
+	}
+	adaptive := min(time.Duration(averageGap)*time.Second/2, adaptiveMaxInterval)
+	return max(interval, adaptive)
+}
+
+// recordFailure bumps the feed's failure count and pushes its next attempt
+// out by feedBackoff. A feed that has failed for feedDisableAfter is
+// disabled until re-enabled with enablefeed.
+func recordFailure(ctx context.Context, q *database.Queries, feedID int32, errorText sql.NullString) error {
+	now := time.Now()
+	failure, err := q.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		LastError: errorText,
+		FailedAt: sql.NullTime{
+			Time:  now,
+			Valid: true,
+		},
+		ID: feedID,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording feed failure: %w", err)
+	}
+
+	if failure.FailingSince.Valid && now.Sub(failure.FailingSince.Time) >= feedDisableAfter {
+		err2 := q.DisableFeed(ctx, database.DisableFeedParams{
+			DisabledAt: sql.NullTime{
+				Time:  now,
+				Valid: true,
+			},
+			ID: feedID,
+		})
+		if err2 != nil {
+			return fmt.Errorf("error disabling feed: %w", err2)
+		}
+		return nil
+	}
+
+	err3 := q.SetFeedBackoff(ctx, database.SetFeedBackoffParams{
+		BackoffSeconds: durationSeconds(feedBackoff(failure.ConsecutiveFailures)),
+		ID:             feedID,
+	})
+	if err3 != nil {
+		return fmt.Errorf("error setting feed backoff: %w", err3)
+	}
+	return nil
+}
+
+// feedBackoff returns how long to wait before retrying a feed that has
+// failed the given number of times in a row, doubling from feedBackoffBase
+// up to feedBackoffMax.
+func feedBackoff(failures int32) time.Duration {
+	backoff := feedBackoffBase
+	for i := int32(1); i < failures; i++ {
+		backoff *= 2
+		if backoff >= feedBackoffMax {
+			return feedBackoffMax
+		}
+	}
+	return backoff
+}
+
+// durationSeconds rounds a duration up to whole seconds for the interval
+// arithmetic done in SQL.
+func durationSeconds(d time.Duration) int32 {
+	if d <= 0 {
+		return 0
+	}
+	return int32((d + time.Second - 1) / time.Second)
+}
+
+// recordFetch appends the attempt to feed_fetch_log and updates the feed's
+// consecutive failure counter and last error.
+func recordFetch(ctx context.Context, q *database.Queries, feedID int32, start time.Time, stats fetchStats, scrapeErr error) error {
+	var errorText sql.NullString
+	if scrapeErr != nil {
+		errorText = sql.NullString{
+			String: scrapeErr.Error(),
+			Valid:  true,
+		}
+	}
+
+	err := q.CreateFeedFetchLog(ctx, database.CreateFeedFetchLogParams{
+		FeedID:    feedID,
+		FetchedAt: start,
+		StatusCode: sql.NullInt32{
+			Int32: int32(stats.statusCode),
+			Valid: stats.statusCode != 0,
+		},
+		DurationMs:   int32(time.Since(start).Milliseconds()),
+		Bytes:        int32(stats.bytes),
+		ItemCount:    int32(stats.itemCount),
+		NewPosts:     int32(stats.newPosts),
+		ErrorText:    errorText,
+		SkippedItems: int32(stats.skippedItems),
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting fetch log: %w", err)
+	}
+
+	if scrapeErr != nil {
+		return recordFailure(ctx, q, feedID, errorText)
+	}
+
+	err3 := q.RecordFeedSuccess(ctx, database.RecordFeedSuccessParams{
+		LastSuccessAt: sql.NullTime{
+			Time:  time.Now(),
+			Valid: true,
+		},
+		ID: feedID,
+	})
+	if err3 != nil {
+		return fmt.Errorf("error recording feed success: %w", err3)
+	}
+	return nil
+}
+
+// itemGUID identifies an item within its feed: the RSS guid, Atom id or
+// JSON Feed id when present, otherwise its normalized link, so a link that
+// gains tracking parameters or loses a trailing slash is still the same post.
+func itemGUID(item rss.RSSItem) string {
+	if guid := strings.TrimSpace(item.GUID); guid != "" {
+		return guid
+	}
+	if normalized, err := urlnorm.Normalize(item.Link); err == nil {
+		return normalized
+	}
+	return item.Link
+}
+
+// uniqueItems drops items repeating an earlier item's GUID, since one upsert
+// cannot touch the same post twice.
+func uniqueItems(items []rss.RSSItem) []rss.RSSItem {
+	seen := make(map[string]bool, len(items))
+	var unique []rss.RSSItem
+	for _, item := range items {
+		guid := itemGUID(item)
+		if seen[guid] {
+			continue
+		}
+		seen[guid] = true
+		unique = append(unique, item)
+	}
+	return unique
+}
+
+// contentHash identifies a post's title and description so edits can be
+// detected. Migration 013 computes the same hash for existing posts. Edits to
+// the other columns are found by comparing them directly in UpsertPosts.
+func contentHash(title, description string) string {
+	sum := sha256.Sum256([]byte(title + "\n" + description))
+	return hex.EncodeToString(sum[:])
+}
+
+// storePostDetails adds the categories and enclosures of a batch of items
+// that have already been upserted into posts.
+func storePostDetails(ctx context.Context, q *database.Queries, feedID int32, items []rss.RSSItem) error {
+	categories := database.CreatePostCategoriesParams{FeedID: feedID}
+	enclosures := database.CreatePostEnclosuresParams{FeedID: feedID}
+	for _, item := range items {
+		guid := itemGUID(item)
+		for _, category := range item.Categories {
+			if category = strings.TrimSpace(category); category != "" {
+				categories.Guids = append(categories.Guids, guid)
+				categories.Names = append(categories.Names, category)
+			}
+		}
+		for _, enclosure := range item.Enclosures {
+			if enclosure.URL == "" {
+				continue
+			}
+			length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+			enclosures.Guids = append(enclosures.Guids, guid)
+			enclosures.Urls = append(enclosures.Urls, enclosure.URL)
+			enclosures.MimeTypes = append(enclosures.MimeTypes, enclosure.Type)
+			enclosures.Lengths = append(enclosures.Lengths, length)
+		}
+	}
+
+	if len(categories.Guids) > 0 {
+		if err := q.CreatePostCategories(ctx, categories); err != nil {
+			return fmt.Errorf("error inserting post categories: %w", err)
+		}
+	}
+	if len(enclosures.Guids) > 0 {
+		if err := q.CreatePostEnclosures(ctx, enclosures); err != nil {
+			return fmt.Errorf("error inserting post enclosures: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchFeed downloads the feed, collecting the response details recorded in
+// feed_fetch_log whether or not the fetch succeeds.
+func fetchFeed(ctx context.Context, fetcher *rss.Fetcher, feed database.ClaimNextFeedRow) (*rss.FetchResult, fetchStats, error) {
+	var stats fetchStats
+	fetchRes, err := fetcher.FetchFeed(ctx, feed.Url, rss.CacheHeaders{
+		ETag:         feed.Etag.String,
+		LastModified: feed.LastModified.String,
+	})
+	if fetchRes != nil {
+		stats.statusCode = fetchRes.StatusCode
+		stats.bytes = fetchRes.Bytes
+		stats.notBefore = fetchRes.NotBefore
+		stats.skippedItems = fetchRes.SkippedItems
+	}
+	if err != nil {
+		return nil, stats, fmt.Errorf("error fetching feed: %w", err)
+	}
+	if fetchRes.Feed != nil {
+		stats.itemCount = len(fetchRes.Feed.Channel.Item)
+	}
+	if stats.skippedItems > 0 {
+		fmt.Printf("skipped %d malformed items in %s\n", stats.skippedItems, feed.Url)
+	}
+	return fetchRes, stats, nil
+}
+
+// storeFeed upserts the feed's items in batches of postBatchSize, keeping the
+// previous version of any post whose title, description or publish date
+// changed in post_revisions, and saves its cache headers and publisher
+// schedule.
+func storeFeed(ctx context.Context, q *database.Queries, feed database.ClaimNextFeedRow, fetchRes *rss.FetchResult, stats *fetchStats) error {
+	currentFeed := fetchRes.Feed
+
+	schedule := currentFeed.Schedule()
+	stats.schedule = &schedule
+	if err := savePublisherSchedule(ctx, q, feed.ID, schedule); err != nil {
+		return fmt.Errorf("error saving publisher schedule: %w", err)
+	}
+
+	fetchedAt := time.Now()
+	items := uniqueItems(currentFeed.Channel.Item)
+	for len(items) > 0 {
+		batch := items[:min(len(items), postBatchSize)]
+		items = items[len(batch):]
+
+		params := database.UpsertPostsParams{
+			FetchedAt: fetchedAt,
+			FeedID:    feed.ID,
+		}
+		for _, item := range batch {
+			parsedDate, dateSource := rss.ParsePubDate(item.PubDate, fetchedAt)
+			if dateSource == rss.DateFetchTime {
+				fmt.Printf("unparsable publish date %q for %s, using fetch time\n", item.PubDate, item.Link)
+			}
+			params.Titles = append(params.Titles, item.Title)
+			params.Urls = append(params.Urls, item.Link)
+			params.UrlKeys = append(params.UrlKeys, urlKey(item.Link).String)
+			description := htmltext.Sanitize(item.Description)
+			params.Descriptions = append(params.Descriptions, description)
+			params.PublishedAts = append(params.PublishedAts, parsedDate)
+			params.PublishedAtSources = append(params.PublishedAtSources, string(dateSource))
+			params.ContentHashes = append(params.ContentHashes, contentHash(item.Title, item.Description))
+			params.Guids = append(params.Guids, itemGUID(item))
+			content := htmltext.Sanitize(item.Content)
+			params.Contents = append(params.Contents, content)
+			params.Authors = append(params.Authors, item.AuthorName())
+			params.CommentsUrls = append(params.CommentsUrls, item.Comments)
+
+			episode := item.PodcastEpisode()
+			params.DurationSeconds = append(params.DurationSeconds, int32(episode.Duration/time.Second))
+			params.Episodes = append(params.Episodes, int32(episode.Episode))
+			params.Seasons = append(params.Seasons, int32(episode.Season))
+			params.ImageUrls = append(params.ImageUrls, episode.ImageURL)
+
+			textSource := description
+			if textSource == "" {
+				textSource = content
+			}
+			params.DescriptionTexts = append(params.DescriptionTexts, htmltext.Render(textSource, postTextWidth))
+		}
+
+		// Posts whose GUID was backfilled from their URL are matched by URL,
+		// or by url_key once they have one, and take the item's GUID, so they
+		// are updated rather than stored a second time.
+		err := q.ReplaceBackfilledGuids(ctx, database.ReplaceBackfilledGuidsParams{
+			Guids:   params.Guids,
+			Urls:    params.Urls,
+			UrlKeys: params.UrlKeys,
+			FeedID:  feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error replacing backfilled post guids: %w", err)
+		}
+
+		// Revisions must be copied before the upsert overwrites them.
+		_, err = q.RecordPostRevisions(ctx, database.RecordPostRevisionsParams{
+			RevisedAt:          fetchedAt,
+			Guids:              params.Guids,
+			PublishedAts:       params.PublishedAts,
+			PublishedAtSources: params.PublishedAtSources,
+			ContentHashes:      params.ContentHashes,
+			Contents:           params.Contents,
+			Authors:            params.Authors,
+			FeedID:             feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording post revisions: %w", err)
+		}
+
+		upserted, err := q.UpsertPosts(ctx, params)
+		if err != nil {
+			return fmt.Errorf("error upserting to posts table: %w", err)
+		}
+		for _, inserted := range upserted {
+			if inserted {
+				stats.newPosts++
+			}
+		}
+
+		if err := storePostDetails(ctx, q, feed.ID, batch); err != nil {
+			return err
+		}
+	}
+
+	err := q.SetFeedCacheHeaders(ctx, database.SetFeedCacheHeadersParams{
+		Etag: sql.NullString{
+			String: fetchRes.Cache.ETag,
+			Valid:  fetchRes.Cache.ETag != "",
+		},
+		LastModified: sql.NullString{
+			String: fetchRes.Cache.LastModified,
+			Valid:  fetchRes.Cache.LastModified != "",
+		},
+		ID: feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving feed cache headers: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Walther-Knight/blogGATOR/internal/database"
+	"github.com/Walther-Knight/blogGATOR/internal/urlnorm"
+)
+
+// handlerDedupeFeeds merges feeds whose URLs only differ before
+// normalization, or by http and https. The oldest https feed of each group is
+// kept with its URL as it was added, and inherits the others' follows, posts
+// and fetch history; posts it already has are dropped from the duplicates.
+// It then fills in the url_key of feeds and aliases added before it existed.
+func handlerDedupeFeeds(s *state, cmd command) error {
+	ctx := context.Background()
+
+	feeds, err := s.db.GetFeedURLs(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving feeds: %w", err)
+	}
+
+	normalized := make(map[int32]string)
+	groups := make(map[string][]database.GetFeedURLsRow)
+	var keys []string
+	for _, feed := range feeds {
+		feedURL, err := urlnorm.Normalize(feed.Url)
+		if err != nil {
+			fmt.Printf("Skipping feed %d: %v\n", feed.ID, err)
+			continue
+		}
+		normalized[feed.ID] = feedURL
+		key := urlnorm.Key(feedURL)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], feed)
+	}
+
+	tx, err2 := s.conn.BeginTx(ctx, nil)
+	if err2 != nil {
+		return fmt.Errorf("error starting transaction: %w", err2)
+	}
+	defer tx.Rollback()
+	qtx := s.db.WithTx(tx)
+
+	merged := 0
+	kept := make(map[string]int32, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		keep := group[0]
+		for _, feed := range group {
+			if strings.HasPrefix(normalized[feed.ID], "https://") && !strings.HasPrefix(normalized[keep.ID], "https://") {
+				keep = feed
+			}
+		}
+
+		kept[key] = keep.ID
+
+		for _, duplicate := range group {
+			if duplicate.ID == keep.ID {
+				continue
+			}
+			if err := mergeFeed(ctx, qtx, keep.ID, duplicate.ID); err != nil {
+				return fmt.Errorf("error merging %s into %s: %w", duplicate.Url, keep.Url, err)
+			}
+			fmt.Printf("Merged %s into %s\n", duplicate.Url, keep.Url)
+			merged++
+		}
+	}
+
+	// Keys are set once every duplicate is gone, as they must be unique.
+	for _, key := range keys {
+		err := qtx.SetFeedURLKey(ctx, database.SetFeedURLKeyParams{
+			ID:     kept[key],
+			UrlKey: sql.NullString{String: key, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error setting feed url key: %w", err)
+		}
+	}
+
+	aliases, err3 := qtx.GetFeedAliasURLs(ctx)
+	if err3 != nil {
+		return fmt.Errorf("error retrieving feed aliases: %w", err3)
+	}
+	for _, alias := range aliases {
+		err := qtx.SetFeedAliasURLKey(ctx, database.SetFeedAliasURLKeyParams{
+			Url:    alias,
+			UrlKey: urlKey(alias),
+		})
+		if err != nil {
+			return fmt.Errorf("error setting feed alias url key: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing feed merge: %w", err)
+	}
+
+	fmt.Printf("Merged %d duplicate feeds\n", merged)
+	return nil
+}
+
+// mergeFeed moves a duplicate feed's follows, posts, fetch log and aliases to
+// the kept feed and deletes the duplicate.
+func mergeFeed(ctx context.Context, q *database.Queries, keepID, duplicateID int32) error {
+	if err := q.MergeFeedFollows(ctx, database.MergeFeedFollowsParams{KeepID: keepID, DuplicateID: duplicateID}); err != nil {
+		return fmt.Errorf("error moving follows: %w", err)
+	}
+	if err := q.DeleteFeedFollows(ctx, duplicateID); err != nil {
+		return fmt.Errorf("error deleting follows: %w", err)
+	}
+	if err := q.MergeFeedPosts(ctx, database.MergeFeedPostsParams{KeepID: keepID, DuplicateID: duplicateID}); err != nil {
+		return fmt.Errorf("error moving posts: %w", err)
+	}
+	if err := q.DeleteFeedPosts(ctx, duplicateID); err != nil {
+		return fmt.Errorf("error deleting posts: %w", err)
+	}
+	if err := q.MergeFeedFetchLog(ctx, database.MergeFeedFetchLogParams{KeepID: keepID, DuplicateID: duplicateID}); err != nil {
+		return fmt.Errorf("error moving fetch log: %w", err)
+	}
+	if err := q.MergeFeedAliases(ctx, database.MergeFeedAliasesParams{KeepID: keepID, DuplicateID: duplicateID}); err != nil {
+		return fmt.Errorf("error moving aliases: %w", err)
+	}
+	if err := q.DeleteFeed(ctx, duplicateID); err != nil {
+		return fmt.Errorf("error deleting feed: %w", err)
+	}
+	return nil
+}
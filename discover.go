@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Walther-Knight/blogGATOR/internal/htmltext"
+	"github.com/Walther-Knight/blogGATOR/internal/rss"
+)
+
+// discoverFeedURL turns a website or feed URL into a feed URL that has been
+// fetched and parsed, asking the user to choose when the site offers more
+// than one feed.
+func discoverFeedURL(ctx context.Context, fetcher *rss.Fetcher, pageURL string) (string, error) {
+	feeds, err := fetcher.Discover(ctx, pageURL)
+	if err != nil {
+		return "", fmt.Errorf("error discovering feed: %w", err)
+	}
+
+	if len(feeds) == 1 {
+		if feeds[0].URL != pageURL {
+			fmt.Printf("Found feed %s\n", feeds[0].URL)
+		}
+		return feeds[0].URL, nil
+	}
+
+	fmt.Printf("Found %d feeds at %s:\n", len(feeds), pageURL)
+	for i, feed := range feeds {
+		fmt.Printf("%d) %s %s\n", i+1, htmltext.StripControls(feed.Title), feed.URL)
+	}
+	fmt.Print("Choose a feed: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	answer, err2 := reader.ReadString('\n')
+	if err2 != nil && answer == "" {
+		return "", fmt.Errorf("error reading feed choice: %w", err2)
+	}
+	choice, err3 := strconv.Atoi(strings.TrimSpace(answer))
+	if err3 != nil || choice < 1 || choice > len(feeds) {
+		return "", fmt.Errorf("invalid feed choice %q", strings.TrimSpace(answer))
+	}
+	return feeds[choice-1].URL, nil
+}
@@ -5,4 +5,5 @@ go 1.24.2
 require (
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	golang.org/x/text v0.34.0
 )
@@ -9,8 +9,27 @@ import (
 const configFileName = ".gatorconfig.json"
 
 type Config struct {
-	DbURL           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DbURL           string      `json:"db_url"`
+	CurrentUserName string      `json:"current_user_name"`
+	DownloadDir     string      `json:"download_dir,omitempty"`
+	HTTP            *HTTPConfig `json:"http,omitempty"`
+}
+
+// HTTPConfig configures the client used to fetch feeds and downloads.
+type HTTPConfig struct {
+	// Timeout is a Go duration such as "10s".
+	Timeout      string                `json:"timeout,omitempty"`
+	MaxBodyBytes int64                 `json:"max_body_bytes,omitempty"`
+	ProxyURL     string                `json:"proxy_url,omitempty"`
+	UserAgent    string                `json:"user_agent,omitempty"`
+	CABundle     string                `json:"ca_bundle,omitempty"`
+	Hosts        map[string]HostConfig `json:"hosts,omitempty"`
+}
+
+// HostConfig holds headers and cookies sent to a single host.
+type HostConfig struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies map[string]string `json:"cookies,omitempty"`
 }
 
 func Read() (Config, error) {
@@ -31,6 +50,19 @@ func (c *Config) SetUser(name string) error {
 	return write(*c)
 }
 
+// Downloads returns the directory podcast episodes are saved to, defaulting
+// to ~/gator-downloads.
+func (c *Config) Downloads() (string, error) {
+	if c.DownloadDir != "" {
+		return c.DownloadDir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to open home directory: %w", err)
+	}
+	return homeDir + "/gator-downloads", nil
+}
+
 func getConfigPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
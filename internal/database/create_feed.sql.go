@@ -7,21 +7,23 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (created_at, updated_at, name, url, user_id, url_key)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, consecutive_failures, last_error, last_success_at, failing_since, backoff_until, disabled_at, fetch_interval_seconds, adaptive_schedule, next_fetch_at, ttl_minutes, skip_hours, skip_days, url_key
 `
 
 type CreateFeedParams struct {
@@ -30,6 +32,7 @@ type CreateFeedParams struct {
 	Name      string
 	Url       string
 	UserID    uuid.UUID
+	UrlKey    sql.NullString
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -39,6 +42,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.UrlKey,
 	)
 	var i Feed
 	err := row.Scan(
@@ -49,6 +53,22 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastSuccessAt,
+		&i.FailingSince,
+		&i.BackoffUntil,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.AdaptiveSchedule,
+		&i.NextFetchAt,
+		&i.TtlMinutes,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.UrlKey,
 	)
 	return i, err
 }
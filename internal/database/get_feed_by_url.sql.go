@@ -7,10 +7,11 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const getFeed = `-- name: GetFeed :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, consecutive_failures, last_error, last_success_at, failing_since, backoff_until, disabled_at, fetch_interval_seconds, adaptive_schedule, next_fetch_at, ttl_minutes, skip_hours, skip_days, url_key
 FROM feeds
 WHERE url = $1
 `
@@ -26,6 +27,59 @@ func (q *Queries) GetFeed(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastSuccessAt,
+		&i.FailingSince,
+		&i.BackoffUntil,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.AdaptiveSchedule,
+		&i.NextFetchAt,
+		&i.TtlMinutes,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.UrlKey,
+	)
+	return i, err
+}
+
+const getFeedByURLKey = `-- name: GetFeedByURLKey :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, consecutive_failures, last_error, last_success_at, failing_since, backoff_until, disabled_at, fetch_interval_seconds, adaptive_schedule, next_fetch_at, ttl_minutes, skip_hours, skip_days, url_key
+FROM feeds
+WHERE url_key = $1
+`
+
+func (q *Queries) GetFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURLKey, urlKey)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastSuccessAt,
+		&i.FailingSince,
+		&i.BackoffUntil,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.AdaptiveSchedule,
+		&i.NextFetchAt,
+		&i.TtlMinutes,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.UrlKey,
 	)
 	return i, err
 }
@@ -12,7 +12,18 @@ import (
 )
 
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
-SELECT feeds.name, users.name
+SELECT feeds.name, users.name, (
+    SELECT COUNT(*)
+    FROM posts
+    WHERE posts.feed_id = feeds.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_post_state
+        WHERE user_post_state.user_id = feed_follows.user_id
+        AND user_post_state.post_id = posts.id
+        AND user_post_state.read
+    )
+) AS unread_count
 FROM feed_follows
 INNER JOIN feeds
 ON feed_follows.feed_id = feeds.id
@@ -22,8 +33,9 @@ WHERE feed_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	Name   string
-	Name_2 string
+	Name        string
+	Name_2      string
+	UnreadCount int64
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -35,7 +47,7 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	var items []GetFeedFollowsForUserRow
 	for rows.Next() {
 		var i GetFeedFollowsForUserRow
-		if err := rows.Scan(&i.Name, &i.Name_2); err != nil {
+		if err := rows.Scan(&i.Name, &i.Name_2, &i.UnreadCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
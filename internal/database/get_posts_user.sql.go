@@ -14,42 +14,63 @@ import (
 )
 
 const getPostsByUser = `-- name: GetPostsByUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, title, url, description, published_at, posts.feed_id, feed_follows.id, feed_follows.created_at, feed_follows.updated_at, user_id, feed_follows.feed_id
+SELECT posts.id, posts.created_at, posts.updated_at, title, url, description, published_at, posts.feed_id, published_at_source, content_hash, guid, content, author, comments_url, duration_seconds, episode, season, image_url, description_text, guid_backfilled, url_key, published_at_utc, feed_follows.id, feed_follows.created_at, feed_follows.updated_at, user_id, feed_follows.feed_id
 FROM posts
 INNER JOIN feed_follows
 ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.feed_id IN (
-    SELECT feed_id
-    FROM feed_follows
-    WHERE feed_follows.user_id = $1
+WHERE feed_follows.user_id = $1
+AND (
+    NOT $2::BOOLEAN
+    OR NOT EXISTS (
+        SELECT 1
+        FROM user_post_state
+        WHERE user_post_state.user_id = $1
+        AND user_post_state.post_id = posts.id
+        AND user_post_state.read
+    )
 )
 ORDER BY published_at DESC
-LIMIT $2
+LIMIT $3
 `
 
 type GetPostsByUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int32
 }
 
 type GetPostsByUserRow struct {
-	ID          int32
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt time.Time
-	FeedID      int32
-	ID_2        int32
-	CreatedAt_2 time.Time
-	UpdatedAt_2 time.Time
-	UserID      uuid.UUID
-	FeedID_2    int32
+	ID                int32
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Title             string
+	Url               string
+	Description       sql.NullString
+	PublishedAt       time.Time
+	FeedID            int32
+	PublishedAtSource string
+	ContentHash       sql.NullString
+	Guid              string
+	Content           sql.NullString
+	Author            sql.NullString
+	CommentsUrl       sql.NullString
+	DurationSeconds   sql.NullInt32
+	Episode           sql.NullInt32
+	Season            sql.NullInt32
+	ImageUrl          sql.NullString
+	DescriptionText   sql.NullString
+	GuidBackfilled    bool
+	UrlKey            sql.NullString
+	PublishedAtUtc    bool
+	ID_2              int32
+	CreatedAt_2       time.Time
+	UpdatedAt_2       time.Time
+	UserID            uuid.UUID
+	FeedID_2          int32
 }
 
 func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams) ([]GetPostsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsByUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsByUser, arg.UserID, arg.UnreadOnly, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +87,20 @@ func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams)
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.PublishedAtSource,
+			&i.ContentHash,
+			&i.Guid,
+			&i.Content,
+			&i.Author,
+			&i.CommentsUrl,
+			&i.DurationSeconds,
+			&i.Episode,
+			&i.Season,
+			&i.ImageUrl,
+			&i.DescriptionText,
+			&i.GuidBackfilled,
+			&i.UrlKey,
+			&i.PublishedAtUtc,
 			&i.ID_2,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
@@ -12,16 +12,17 @@ import (
 
 const markFeed = `-- name: MarkFeed :exec
 UPDATE feeds
-SET last_fetched_at = $1
-WHERE id = $2
+SET last_fetched_at = $1, next_fetch_at = now() + make_interval(secs => $2::INTEGER), claimed_until = NULL
+WHERE id = $3
 `
 
 type MarkFeedParams struct {
-	LastFetchedAt sql.NullTime
-	ID            int32
+	LastFetchedAt    sql.NullTime
+	NextFetchSeconds int32
+	ID               int32
 }
 
 func (q *Queries) MarkFeed(ctx context.Context, arg MarkFeedParams) error {
-	_, err := q.db.ExecContext(ctx, markFeed, arg.LastFetchedAt, arg.ID)
+	_, err := q.db.ExecContext(ctx, markFeed, arg.LastFetchedAt, arg.NextFetchSeconds, arg.ID)
 	return err
 }
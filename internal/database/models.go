@@ -12,13 +12,49 @@ import (
 )
 
 type Feed struct {
-	ID            int32
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                   int32
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Name                 string
+	Url                  string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	ClaimedUntil         sql.NullTime
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	ConsecutiveFailures  int32
+	LastError            sql.NullString
+	LastSuccessAt        sql.NullTime
+	FailingSince         sql.NullTime
+	BackoffUntil         sql.NullTime
+	DisabledAt           sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	AdaptiveSchedule     bool
+	NextFetchAt          sql.NullTime
+	TtlMinutes           sql.NullInt32
+	SkipHours            string
+	SkipDays             string
+	UrlKey               sql.NullString
+}
+
+type FeedAlias struct {
+	Url       string
+	FeedID    int32
+	CreatedAt time.Time
+	UrlKey    sql.NullString
+}
+
+type FeedFetchLog struct {
+	ID           int32
+	FeedID       int32
+	FetchedAt    time.Time
+	StatusCode   sql.NullInt32
+	DurationMs   int32
+	Bytes        int32
+	ItemCount    int32
+	NewPosts     int32
+	ErrorText    sql.NullString
+	SkippedItems int32
 }
 
 type FeedFollow struct {
@@ -30,14 +66,62 @@ type FeedFollow struct {
 }
 
 type Post struct {
+	ID                int32
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Title             string
+	Url               string
+	Description       sql.NullString
+	PublishedAt       time.Time
+	FeedID            int32
+	PublishedAtSource string
+	ContentHash       sql.NullString
+	Guid              string
+	Content           sql.NullString
+	Author            sql.NullString
+	CommentsUrl       sql.NullString
+	DurationSeconds   sql.NullInt32
+	Episode           sql.NullInt32
+	Season            sql.NullInt32
+	ImageUrl          sql.NullString
+	DescriptionText   sql.NullString
+	GuidBackfilled    bool
+	UrlKey            sql.NullString
+	PublishedAtUtc    bool
+}
+
+type PostCategory struct {
+	PostID int32
+	Name   string
+}
+
+type PostDownload struct {
+	PostID       int32
+	Url          string
+	Path         string
+	Bytes        int64
+	Sha256       string
+	DownloadedAt time.Time
+}
+
+type PostEnclosure struct {
+	ID       int32
+	PostID   int32
+	Url      string
+	MimeType sql.NullString
+	Length   sql.NullInt64
+}
+
+type PostRevision struct {
 	ID          int32
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	PostID      int32
+	RevisedAt   time.Time
 	Title       string
-	Url         string
 	Description sql.NullString
 	PublishedAt time.Time
-	FeedID      int32
+	ContentHash sql.NullString
+	Content     sql.NullString
+	Author      sql.NullString
 }
 
 type User struct {
@@ -46,3 +130,10 @@ type User struct {
 	UpdatedAt time.Time
 	Name      string
 }
+
+type UserPostState struct {
+	UserID    uuid.UUID
+	PostID    int32
+	Read      bool
+	UpdatedAt time.Time
+}
@@ -0,0 +1,208 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Result describes a completed download.
+type Result struct {
+	Path    string
+	Bytes   int64
+	SHA256  string
+	Resumed bool
+}
+
+// validatorSuffix names the file kept next to a partial download with the
+// ETag or Last-Modified of the response it came from.
+const validatorSuffix = ".resume"
+
+// File streams fileURL to path using client. If path already holds part of
+// the file the download resumes from its end with a Range request. The
+// request carries If-Range with the ETag or Last-Modified saved from the
+// first response, so a file that changed since is sent whole and replaces the
+// partial copy, as it is when the server ignores the range or answers with a
+// different one. A partial file without a saved validator is downloaded
+// again. The checksum always covers the complete file on disk.
+func File(ctx context.Context, client *http.Client, fileURL, path string) (Result, error) {
+	result := Result{Path: path}
+	validatorPath := path + validatorSuffix
+
+	out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return result, fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer out.Close()
+
+	sum := sha256.New()
+	existing, err := io.Copy(sum, out)
+	if err != nil {
+		return result, fmt.Errorf("error reading partial download: %w", err)
+	}
+
+	validator := ""
+	if data, err := os.ReadFile(validatorPath); err == nil {
+		validator = strings.TrimSpace(string(data))
+	}
+	if existing > 0 && validator == "" {
+		// Nothing tells whether the partial copy is of the current file.
+		if err := discard(out, sum); err != nil {
+			return result, err
+		}
+		existing = 0
+	}
+
+	var res *http.Response
+	for res == nil {
+		res, err = get(ctx, client, fileURL, existing, validator)
+		if err != nil {
+			return result, err
+		}
+
+		switch {
+		case res.StatusCode == http.StatusPartialContent && existing > 0:
+			if start, _, ok := contentRange(res.Header.Get("Content-Range")); ok && start == existing {
+				result.Resumed = true
+				continue
+			}
+		case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && existing > 0:
+			if _, total, ok := contentRange(res.Header.Get("Content-Range")); ok && total == existing {
+				// The partial file is already the whole file.
+				res.Body.Close()
+				os.Remove(validatorPath)
+				result.Bytes = existing
+				result.SHA256 = hex.EncodeToString(sum.Sum(nil))
+				return result, nil
+			}
+		case res.StatusCode >= 200 && res.StatusCode <= 299 && res.StatusCode != http.StatusPartialContent:
+			if err := discard(out, sum); err != nil {
+				res.Body.Close()
+				return result, err
+			}
+			existing = 0
+			if err := saveValidator(validatorPath, res); err != nil {
+				res.Body.Close()
+				return result, err
+			}
+			continue
+		default:
+			res.Body.Close()
+			return result, fmt.Errorf("unexpected http status: %s", res.Status)
+		}
+
+		// The server answered with a range other than the one asked for;
+		// start again from the beginning.
+		res.Body.Close()
+		res = nil
+		if err := discard(out, sum); err != nil {
+			return result, err
+		}
+		existing = 0
+	}
+	defer res.Body.Close()
+
+	written, err := io.Copy(io.MultiWriter(out, sum), res.Body)
+	result.Bytes = existing + written
+	if err != nil {
+		return result, fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	os.Remove(validatorPath)
+	result.SHA256 = hex.EncodeToString(sum.Sum(nil))
+	return result, nil
+}
+
+// get requests fileURL, asking for the bytes after existing when the partial
+// copy's validator is known.
+func get(ctx context.Context, client *http.Client, fileURL string, existing int64, validator string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %w", err)
+	}
+	if existing > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(existing, 10)+"-")
+		req.Header.Set("If-Range", validator)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error with http client response: %w", err)
+	}
+	return res, nil
+}
+
+// discard empties the partial download so it can be written from the start.
+func discard(out *os.File, sum hash.Hash) error {
+	if err := out.Truncate(0); err != nil {
+		return fmt.Errorf("error discarding partial download: %w", err)
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error discarding partial download: %w", err)
+	}
+	sum.Reset()
+	return nil
+}
+
+// saveValidator keeps the response's strong ETag, or else its Last-Modified,
+// for resuming the download later. If-Range does not accept weak ETags.
+func saveValidator(validatorPath string, res *http.Response) error {
+	validator := res.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = res.Header.Get("Last-Modified")
+	}
+	if validator == "" {
+		if err := os.Remove(validatorPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error removing download validator: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(validatorPath, []byte(validator), 0644); err != nil {
+		return fmt.Errorf("error saving download validator: %w", err)
+	}
+	return nil
+}
+
+// contentRange parses a Content-Range header such as "bytes 100-199/200" or
+// "bytes */200", returning the first byte and the total size. The total is -1
+// when the server does not know it.
+func contentRange(header string) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	byteRange, totalText, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	total := int64(-1)
+	if totalText != "*" {
+		parsed, err := strconv.ParseInt(totalText, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total = parsed
+	}
+	if byteRange == "*" {
+		return 0, total, true
+	}
+
+	startText, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
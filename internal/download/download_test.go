@@ -0,0 +1,217 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFile(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	const etag = `"v2"`
+	digest := sha256.Sum256(content)
+	wantSum := hex.EncodeToString(digest[:])
+
+	// serve answers like a server that honours Range and If-Range.
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "episode.mp3", time.Time{}, bytes.NewReader(content))
+	}
+
+	tests := []struct {
+		name        string
+		partial     []byte
+		validator   string
+		handler     http.HandlerFunc
+		wantResumed bool
+		wantRanges  []string
+		wantIfRange string
+	}{
+		{
+			name:       "fresh download",
+			handler:    serve,
+			wantRanges: []string{""},
+		},
+		{
+			name:        "resume",
+			partial:     content[:400],
+			validator:   etag,
+			handler:     serve,
+			wantResumed: true,
+			wantRanges:  []string{"bytes=400-"},
+			wantIfRange: etag,
+		},
+		{
+			name:        "file changed since",
+			partial:     []byte(strings.Repeat("x", 400)),
+			validator:   `"v1"`,
+			handler:     serve,
+			wantRanges:  []string{"bytes=400-"},
+			wantIfRange: `"v1"`,
+		},
+		{
+			name:       "partial without validator",
+			partial:    []byte(strings.Repeat("x", 400)),
+			handler:    serve,
+			wantRanges: []string{""},
+		},
+		{
+			name:        "complete file",
+			partial:     content,
+			validator:   etag,
+			handler:     serve,
+			wantRanges:  []string{"bytes=1000-"},
+			wantIfRange: etag,
+		},
+		{
+			name:      "mismatched content range",
+			partial:   []byte(strings.Repeat("x", 400)),
+			validator: etag,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") == "" {
+					serve(w, r)
+					return
+				}
+				w.Header().Set("Content-Range", "bytes 0-99/"+strconv.Itoa(len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(content[:100])
+			},
+			wantRanges:  []string{"bytes=400-", ""},
+			wantIfRange: etag,
+		},
+		{
+			name:      "unsatisfiable range on a shorter file",
+			partial:   append(append([]byte{}, content...), "extra"...),
+			validator: etag,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") == "" {
+					serve(w, r)
+					return
+				}
+				w.Header().Set("Content-Range", "bytes */"+strconv.Itoa(len(content)))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			},
+			wantRanges:  []string{"bytes=1005-", ""},
+			wantIfRange: etag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges []string
+			ifRange := ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				if v := r.Header.Get("If-Range"); v != "" {
+					ifRange = v
+				}
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "episode.mp3")
+			if tt.partial != nil {
+				if err := os.WriteFile(path, tt.partial, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.validator != "" {
+				if err := os.WriteFile(path+validatorSuffix, []byte(tt.validator), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := File(context.Background(), server.Client(), server.URL, path)
+			if err != nil {
+				t.Fatalf("File() error = %v", err)
+			}
+			if result.Resumed != tt.wantResumed {
+				t.Errorf("File() Resumed = %v, want %v", result.Resumed, tt.wantResumed)
+			}
+			if result.Bytes != int64(len(content)) || result.SHA256 != wantSum {
+				t.Errorf("File() = %d bytes, sha256 %s, want %d bytes, sha256 %s", result.Bytes, result.SHA256, len(content), wantSum)
+			}
+			if strings.Join(ranges, ",") != strings.Join(tt.wantRanges, ",") {
+				t.Errorf("Range headers = %q, want %q", ranges, tt.wantRanges)
+			}
+			if ifRange != tt.wantIfRange {
+				t.Errorf("If-Range = %q, want %q", ifRange, tt.wantIfRange)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("file on disk = %d bytes, want the %d served", len(got), len(content))
+			}
+			if _, err := os.Stat(path + validatorSuffix); !os.IsNotExist(err) {
+				t.Errorf("validator file left behind after a complete download: %v", err)
+			}
+		})
+	}
+}
+
+func TestFileKeepsValidatorForResume(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content[:400])
+		w.(http.Flusher).Flush()
+		// Cut the connection before the whole file is sent.
+		panic(http.ErrAbortHandler)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	if _, err := File(context.Background(), server.Client(), server.URL, path); err == nil {
+		t.Fatal("File() error = nil, want an error for the cut download")
+	}
+	validator, err := os.ReadFile(path + validatorSuffix)
+	if err != nil || string(validator) != `"v1"` {
+		t.Errorf("validator = %q, %v, want %q", validator, err, `"v1"`)
+	}
+}
+
+func TestFileStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	if _, err := File(context.Background(), server.Client(), server.URL, path); err == nil {
+		t.Error("File() error = nil, want an error for a 404")
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantStart int64
+		wantTotal int64
+		wantOK    bool
+	}{
+		{"bytes 100-199/200", 100, 200, true},
+		{"bytes 0-99/*", 0, -1, true},
+		{"bytes */200", 0, 200, true},
+		{"bytes 100-199", 0, 0, false},
+		{"items 0-1/2", 0, 0, false},
+		{"bytes x-1/2", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, total, ok := contentRange(tt.header)
+		if start != tt.wantStart || total != tt.wantTotal || ok != tt.wantOK {
+			t.Errorf("contentRange(%q) = %d, %d, %v, want %d, %d, %v", tt.header, start, total, ok, tt.wantStart, tt.wantTotal, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package htmltext
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// blockTags start and end a paragraph.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "div": true, "dl": true,
+	"figure": true, "footer": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "main": true,
+	"nav": true, "p": true, "section": true, "table": true,
+}
+
+// lineTags start and end a line.
+var lineTags = map[string]bool{
+	"dd": true, "dt": true, "figcaption": true, "tr": true,
+}
+
+type renderedLine struct {
+	text   string
+	prefix string
+	// hang prefixes the continuation lines of a wrapped line.
+	hang string
+	pre  bool
+}
+
+type list struct {
+	ordered bool
+	count   int
+}
+
+type renderer struct {
+	lines   []renderedLine
+	current strings.Builder
+	prefix  string
+	hang    string
+	quotes  int
+	lists   []list
+	pre     int
+	anchors []string
+	links   []string
+}
+
+// Render turns an HTML fragment into plain text for the terminal. Blocks are
+// separated by blank lines, lists are bulleted or numbered, blockquotes are
+// quoted with "> ", lines are wrapped at width (no wrapping when width is
+// zero) and links are numbered in the text and listed at the end. Control
+// characters, including those written as entities, are removed.
+func Render(src string, width int) string {
+	r := &renderer{}
+	dropping := ""
+	dropDepth := 0
+
+	for _, tok := range tokenize(src) {
+		if dropDepth > 0 {
+			switch {
+			case tok.kind == startToken && tok.name == dropping && !tok.selfClosing:
+				dropDepth++
+			case tok.kind == endToken && tok.name == dropping:
+				dropDepth--
+			}
+			continue
+		}
+
+		switch tok.kind {
+		case textToken:
+			r.text(html.UnescapeString(tok.text))
+		case startToken:
+			if droppedTags[tok.name] {
+				if !tok.selfClosing {
+					dropping = tok.name
+					dropDepth = 1
+				}
+				continue
+			}
+			r.start(tok)
+		case endToken:
+			r.end(tok.name)
+		}
+	}
+	r.breakLine()
+
+	return r.format(width)
+}
+
+func (r *renderer) text(s string) {
+	if r.pre > 0 {
+		parts := strings.Split(s, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				r.breakLine()
+			}
+			r.current.WriteString(part)
+		}
+		return
+	}
+
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" && r.current.Len() > 0 {
+			r.space()
+		}
+		return
+	}
+	if isSpace(s[0]) {
+		r.space()
+	}
+	for i, word := range words {
+		if i > 0 {
+			r.space()
+		}
+		r.current.WriteString(word)
+	}
+	if isSpace(s[len(s)-1]) {
+		r.space()
+	}
+}
+
+func (r *renderer) space() {
+	current := r.current.String()
+	if current != "" && !strings.HasSuffix(current, " ") {
+		r.current.WriteByte(' ')
+	}
+}
+
+func (r *renderer) start(tok token) {
+	switch {
+	case tok.name == "br":
+		r.breakLine()
+	case tok.name == "hr":
+		r.breakParagraph()
+		r.current.WriteString("----")
+		r.breakParagraph()
+	case tok.name == "img":
+		if alt := strings.TrimSpace(tok.attr("alt")); alt != "" {
+			r.text("[image: " + alt + "]")
+		} else {
+			r.text("[image]")
+		}
+	case tok.name == "a":
+		r.anchors = append(r.anchors, tok.attr("href"))
+	case tok.name == "td" || tok.name == "th":
+		r.space()
+	case tok.name == "pre":
+		r.breakParagraph()
+		r.pre++
+	case tok.name == "blockquote":
+		r.breakParagraph()
+		r.quotes++
+	case tok.name == "ul" || tok.name == "ol":
+		if len(r.lists) == 0 {
+			r.breakParagraph()
+		} else {
+			r.breakLine()
+		}
+		r.lists = append(r.lists, list{ordered: tok.name == "ol"})
+	case tok.name == "li":
+		r.breakLine()
+		marker := "- "
+		if len(r.lists) > 0 {
+			l := &r.lists[len(r.lists)-1]
+			l.count++
+			if l.ordered {
+				marker = fmt.Sprintf("%d. ", l.count)
+			}
+		}
+		base := r.basePrefix()
+		r.prefix = base + marker
+		r.hang = base + strings.Repeat(" ", len(marker))
+	case blockTags[tok.name]:
+		r.breakParagraph()
+	case lineTags[tok.name]:
+		r.breakLine()
+	}
+}
+
+func (r *renderer) end(name string) {
+	switch {
+	case name == "a":
+		if len(r.anchors) == 0 {
+			return
+		}
+		href := r.anchors[len(r.anchors)-1]
+		r.anchors = r.anchors[:len(r.anchors)-1]
+		if href == "" || strings.HasPrefix(href, "#") || !safeURL(href) {
+			return
+		}
+		r.current.WriteString(fmt.Sprintf("[%d]", r.footnote(href)))
+	case name == "pre":
+		r.breakLine()
+		if r.pre > 0 {
+			r.pre--
+		}
+		r.breakParagraph()
+	case name == "blockquote":
+		r.breakParagraph()
+		if r.quotes > 0 {
+			r.quotes--
+		}
+	case name == "ul" || name == "ol":
+		r.breakLine()
+		r.prefix, r.hang = "", ""
+		if len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+		if len(r.lists) == 0 {
+			r.breakParagraph()
+		}
+	case name == "li":
+		r.breakLine()
+		r.prefix, r.hang = "", ""
+	case lineTags[name]:
+		r.breakLine()
+	case blockTags[name]:
+		r.breakParagraph()
+	}
+}
+
+// footnote returns the number of a link, reusing it for repeated links.
+func (r *renderer) footnote(href string) int {
+	for i, link := range r.links {
+		if link == href {
+			return i + 1
+		}
+	}
+	r.links = append(r.links, href)
+	return len(r.links)
+}
+
+// basePrefix is the quoting and list indentation for a new line.
+func (r *renderer) basePrefix() string {
+	prefix := strings.Repeat("> ", r.quotes)
+	if len(r.lists) > 1 {
+		prefix += strings.Repeat("  ", len(r.lists)-1)
+	}
+	return prefix
+}
+
+func (r *renderer) breakLine() {
+	text := r.current.String()
+	if r.pre == 0 {
+		text = strings.TrimSpace(text)
+	}
+	if text != "" || r.pre > 0 {
+		prefix, hang := r.prefix, r.hang
+		if prefix == "" {
+			prefix = r.basePrefix()
+			hang = prefix
+		}
+		r.lines = append(r.lines, renderedLine{text: text, prefix: prefix, hang: hang, pre: r.pre > 0})
+		// Lines after the first in a list item hang under its text.
+		r.prefix = r.hang
+	}
+	r.current.Reset()
+}
+
+func (r *renderer) breakParagraph() {
+	r.breakLine()
+	r.prefix, r.hang = "", ""
+	if len(r.lines) > 0 && r.lines[len(r.lines)-1].text != "" {
+		r.lines = append(r.lines, renderedLine{})
+	}
+}
+
+func (r *renderer) format(width int) string {
+	var out []string
+	for _, line := range r.lines {
+		if line.text == "" && !line.pre {
+			out = append(out, "")
+			continue
+		}
+		if line.pre || width <= 0 {
+			out = append(out, line.prefix+line.text)
+			continue
+		}
+		out = append(out, wrap(line.text, line.prefix, line.hang, width)...)
+	}
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	for len(out) > 0 && out[0] == "" {
+		out = out[1:]
+	}
+
+	if len(r.links) > 0 {
+		out = append(out, "")
+		for i, link := range r.links {
+			out = append(out, fmt.Sprintf("[%d] %s", i+1, link))
+		}
+	}
+	return StripControls(strings.Join(out, "\n"))
+}
+
+// StripControls removes C0 and C1 control characters other than newline and
+// tab, so that text from a feed cannot send escape sequences to the terminal.
+func StripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// wrap breaks text into lines of at most width characters, starting the
+// first with prefix and the rest with hang. Words longer than a line are
+// left whole.
+func wrap(text, prefix, hang string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	line.WriteString(prefix)
+	lineLen := utf8.RuneCountInString(prefix)
+	empty := true
+
+	for _, word := range strings.Fields(text) {
+		wordLen := utf8.RuneCountInString(word)
+		if !empty && lineLen+1+wordLen > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			line.WriteString(hang)
+			lineLen = utf8.RuneCountInString(hang)
+			empty = true
+		}
+		if !empty {
+			line.WriteByte(' ')
+			lineLen++
+		}
+		line.WriteString(word)
+		lineLen += wordLen
+		empty = false
+	}
+	return append(lines, line.String())
+}
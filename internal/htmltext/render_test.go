@@ -0,0 +1,68 @@
+package htmltext
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		width int
+		want  string
+	}{
+		{"plain text", "Hello   world", 0, "Hello world"},
+		{"entities", "AT&amp;T &lt;3 &eacute;", 0, "AT&T <3 é"},
+		{"paragraphs", "<p>One</p><p>Two</p>", 0, "One\n\nTwo"},
+		{"line break", "a<br>b", 0, "a\nb"},
+		{"inline tags", "a <b>bold</b> and <i>italic</i> text", 0, "a bold and italic text"},
+		{"unordered list", "<ul><li>one</li><li>two</li></ul>", 0, "- one\n- two"},
+		{"ordered list", "<ol><li>one</li><li>two</li></ol>", 0, "1. one\n2. two"},
+		{"nested list", "<ul><li>a<ul><li>b</li></ul></li></ul>", 0, "- a\n  - b"},
+		{"blockquote", "<blockquote><p>quoted</p></blockquote>after", 0, "> quoted\n\nafter"},
+		{"pre keeps lines", "<pre>a  b\n  c</pre>", 0, "a  b\n  c"},
+		{"link footnotes", `<a href="https://a.example">A</a> and <a href="https://b.example">B</a> and <a href="https://a.example">A</a>`, 0, "A[1] and B[2] and A[1]\n\n[1] https://a.example\n[2] https://b.example"},
+		{"unsafe link has no footnote", `<a href="javascript:alert(1)">x</a>`, 0, "x"},
+		{"image alt", `<img src="x.png" alt="A cat">`, 0, "[image: A cat]"},
+		{"script dropped", "a<script>alert(1)</script>b", 0, "ab"},
+		{"unclosed tags", "<p><b>bold", 0, "bold"},
+		{"wrapping", "one two three four five", 9, "one two\nthree\nfour five"},
+		{"wrapped list item hangs", "<ul><li>one two three</li></ul>", 9, "- one two\n  three"},
+
+		{"escape character entity", "a&#27;[31mred", 0, "a[31mred"},
+		{"hex escape character entity", "a&#x1b;]0;title&#x07;b", 0, "a]0;titleb"},
+		{"C1 entity read as windows-1252", "a&#x9b;31mb", 0, "a›31mb"},
+		{"raw CSI", "a\u009b31mb", 0, "a31mb"},
+		{"raw control characters", "a\x1b[2Jb\x07c", 0, "a[2Jbc"},
+		{"control character in link", `<a href="https://x.example/&#27;[2J">x</a>`, 0, "x[1]\n\n[1] https://x.example/[2J"},
+		{"control character in alt", `<img alt="&#27;[2Jcat">`, 0, "[image: [2Jcat]"},
+		{"tab kept in pre", "<pre>a\tb</pre>", 0, "a\tb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, tt.width); got != tt.want {
+				t.Errorf("Render(%q, %d) = %q, want %q", tt.src, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripControls(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"line\nbreak\ttab", "line\nbreak\ttab"},
+		{"esc\x1b[0m", "esc[0m"},
+		{"bell\x07", "bell"},
+		{"del\x7f", "del"},
+		{"csi\u009b1m", "csi1m"},
+		{"café", "café"},
+	}
+
+	for _, tt := range tests {
+		if got := StripControls(tt.in); got != tt.want {
+			t.Errorf("StripControls(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package htmltext
+
+import (
+	"html"
+	"strings"
+)
+
+// allowedTags maps each tag kept by Sanitize to the attributes it may keep.
+var allowedTags = map[string][]string{
+	"a":          {"href", "title"},
+	"abbr":       {"title"},
+	"b":          nil,
+	"blockquote": {"cite"},
+	"br":         nil,
+	"code":       nil,
+	"dd":         nil,
+	"del":        nil,
+	"div":        nil,
+	"dl":         nil,
+	"dt":         nil,
+	"em":         nil,
+	"figcaption": nil,
+	"figure":     nil,
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "title", "width", "height"},
+	"ins":        nil,
+	"li":         nil,
+	"ol":         nil,
+	"p":          nil,
+	"pre":        nil,
+	"q":          {"cite"},
+	"s":          nil,
+	"small":      nil,
+	"span":       nil,
+	"strong":     nil,
+	"sub":        nil,
+	"sup":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         {"colspan", "rowspan"},
+	"tfoot":      nil,
+	"th":         {"colspan", "rowspan"},
+	"thead":      nil,
+	"tr":         nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// voidTags never have content or an end tag.
+var voidTags = map[string]bool{
+	"br":  true,
+	"hr":  true,
+	"img": true,
+}
+
+// droppedTags are removed along with everything inside them.
+var droppedTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"noscript": true,
+	"template": true,
+	"svg":      true,
+	"math":     true,
+	"head":     true,
+	"title":    true,
+	"textarea": true,
+	"select":   true,
+}
+
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+	"cite": true,
+}
+
+// Sanitize reduces an HTML fragment to an allowlist of formatting tags and
+// attributes. Scripts, styles and embedded objects are removed with their
+// content, other tags are dropped but keep their text, links may only use
+// http, https, mailto or relative URLs, and every tag left open is closed.
+func Sanitize(src string) string {
+	var out strings.Builder
+	var open []string
+	dropDepth := 0
+	var dropping string
+
+	for _, tok := range tokenize(src) {
+		if dropDepth > 0 {
+			switch {
+			case tok.kind == startToken && tok.name == dropping && !tok.selfClosing:
+				dropDepth++
+			case tok.kind == endToken && tok.name == dropping:
+				dropDepth--
+			}
+			continue
+		}
+
+		switch tok.kind {
+		case textToken:
+			out.WriteString(html.EscapeString(html.UnescapeString(tok.text)))
+
+		case startToken:
+			if droppedTags[tok.name] {
+				if !tok.selfClosing {
+					dropping = tok.name
+					dropDepth = 1
+				}
+				continue
+			}
+			allowedAttrs, ok := allowedTags[tok.name]
+			if !ok {
+				continue
+			}
+			out.WriteString("<" + tok.name)
+			for _, name := range allowedAttrs {
+				value := tok.attr(name)
+				if value == "" || (urlAttributes[name] && !safeURL(value)) {
+					continue
+				}
+				out.WriteString(" " + name + `="` + html.EscapeString(value) + `"`)
+			}
+			out.WriteString(">")
+			if !voidTags[tok.name] {
+				if tok.selfClosing {
+					out.WriteString("</" + tok.name + ">")
+				} else {
+					open = append(open, tok.name)
+				}
+			}
+
+		case endToken:
+			// Close back to the matching open tag; an end tag that was never
+			// opened is dropped.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != tok.name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+	return out.String()
+}
+
+// safeURL reports whether a link target is relative or uses a scheme that
+// cannot run script.
+func safeURL(value string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return true
+	}
+	switch strings.ToLower(cleaned[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
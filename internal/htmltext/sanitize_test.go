@@ -0,0 +1,58 @@
+package htmltext
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"plain text", "a & b < c", "a &amp; b &lt; c"},
+		{"allowed formatting", "<p>Hi <b>there</b> <em>you</em></p>", "<p>Hi <b>there</b> <em>you</em></p>"},
+		{"upper-case tags", "<P>Hi <B>there</B></P>", "<p>Hi <b>there</b></p>"},
+		{"link kept", `<a href="https://example.com/a?b=1&amp;c=2" title="T">x</a>`, `<a href="https://example.com/a?b=1&amp;c=2" title="T">x</a>`},
+		{"relative link kept", `<a href="/post/1">x</a>`, `<a href="/post/1">x</a>`},
+		{"unknown tag keeps text", "<font color=red>red</font>", "red"},
+
+		{"script removed with content", "a<script>alert(1)</script>b", "ab"},
+		{"upper-case script", "a<SCRIPT>alert(1)</SCRIPT>b", "ab"},
+		{"nested script", "a<script><script>x</script>alert(1)</script>b", "ab"},
+		{"unclosed script drops the rest", "a<script>alert(1)", "a"},
+		{"split script tag", "<scr<script>ipt>alert(1)</script>", "ipt&gt;alert(1)"},
+		{"style removed", "<style>body{display:none}</style>text", "text"},
+		{"iframe removed", `<iframe src="https://evil.example"></iframe>ok`, "ok"},
+		{"svg removed", `<svg onload="alert(1)"><circle/></svg>ok`, "ok"},
+		{"comment removed", "a<!-- <script>alert(1)</script> -->b", "ab"},
+
+		{"event handler dropped", `<p onclick="alert(1)">x</p>`, "<p>x</p>"},
+		{"img onerror dropped", `<img src="x.png" onerror="alert(1)">`, `<img src="x.png">`},
+		{"style attribute dropped", `<p style="background:url(javascript:alert(1))">x</p>`, "<p>x</p>"},
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"mixed-case javascript", `<a href="JaVaScRiPt:alert(1)">x</a>`, "<a>x</a>"},
+		{"javascript with tab", "<a href=\"java\tscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"javascript with entity tab", `<a href="java&#x09;script:alert(1)">x</a>`, "<a>x</a>"},
+		{"javascript with entity letters", `<a href="&#106;avascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"javascript with leading space", `<a href=" javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"javascript with newline entity", `<a href="javascript&#10;:alert(1)">x</a>`, "<a>x</a>"},
+		{"data image", `<img src="data:text/html;base64,PHNjcmlwdD4=">`, "<img>"},
+		{"vbscript", `<a href="vbscript:msgbox(1)">x</a>`, "<a>x</a>"},
+		{"quote breakout in attribute", `<a title='"><script>alert(1)</script>'>x</a>`, `<a title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">x</a>`},
+		{"unquoted attribute", `<a href=https://example.com onmouseover=alert(1)>x</a>`, `<a href="https://example.com">x</a>`},
+
+		{"unclosed tags closed", "<p><b>bold", "<p><b>bold</b></p>"},
+		{"misnested tags", "<div><b>x</div>y", "<div><b>x</b></div>y"},
+		{"stray end tag dropped", "x</b></div>", "x"},
+		{"self-closing non-void", "<b/>x", "<b></b>x"},
+		{"broken tag kept as text", "a <b and c", "a &lt;b and c"},
+		{"escaped markup stays escaped", "&lt;script&gt;alert(1)&lt;/script&gt;", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.src); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package htmltext
+
+import (
+	"html"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	startToken
+	endToken
+)
+
+type attribute struct {
+	name  string
+	value string
+}
+
+// token is a piece of an HTML fragment. Text is still escaped as in the
+// source; attribute values have been unescaped.
+type token struct {
+	kind        tokenKind
+	name        string
+	attrs       []attribute
+	selfClosing bool
+	text        string
+}
+
+// tokenize splits an HTML fragment into text and tags. Comments, doctypes and
+// processing instructions are dropped, and a '<' that does not open a tag is
+// kept as text, so any input produces tokens.
+func tokenize(src string) []token {
+	var tokens []token
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{kind: textToken, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(src); {
+		if src[i] != '<' {
+			next := strings.IndexByte(src[i:], '<')
+			if next < 0 {
+				next = len(src) - i
+			}
+			text.WriteString(src[i : i+next])
+			i += next
+			continue
+		}
+
+		rest := src[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += 4 + end + 3
+			}
+			continue
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 1
+			}
+			continue
+		}
+
+		tag, length, ok := parseTag(rest)
+		if !ok {
+			text.WriteByte('<')
+			i++
+			continue
+		}
+		flush()
+		tokens = append(tokens, tag)
+		i += length
+	}
+	flush()
+	return tokens
+}
+
+// parseTag reads a start or end tag at the beginning of s, returning the
+// token and its length in bytes.
+func parseTag(s string) (token, int, bool) {
+	i := 1
+	tag := token{kind: startToken}
+	if i < len(s) && s[i] == '/' {
+		tag.kind = endToken
+		i++
+	}
+
+	start := i
+	for i < len(s) && isNameByte(s[i]) {
+		i++
+	}
+	if i == start || !isLetter(s[start]) {
+		return token{}, 0, false
+	}
+	tag.name = strings.ToLower(s[start:i])
+
+	for i < len(s) {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '>':
+			return tag, i + 1, true
+		case '/':
+			tag.selfClosing = true
+			i++
+			continue
+		}
+
+		nameStart := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					return token{}, 0, false
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[valueStart:i]
+			}
+		}
+		if name != "" && tag.kind == startToken {
+			tag.attrs = append(tag.attrs, attribute{name: name, value: html.UnescapeString(value)})
+		}
+	}
+	return token{}, 0, false
+}
+
+func (t token) attr(name string) string {
+	for _, a := range t.attrs {
+		if a.name == name {
+			return a.value
+		}
+	}
+	return ""
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isNameByte(b byte) bool {
+	return isLetter(b) || (b >= '0' && b <= '9') || b == '-' || b == ':'
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
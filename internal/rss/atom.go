@@ -0,0 +1,138 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type atomFeed struct {
+	Title    atomText    `xml:"title"`
+	Subtitle atomText    `xml:"subtitle"`
+	Link     []atomLink  `xml:"link"`
+	Entry    []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string         `xml:"id"`
+	Title     atomText       `xml:"title"`
+	Link      []atomLink     `xml:"link"`
+	Updated   string         `xml:"updated"`
+	Published string         `xml:"published"`
+	Summary   atomText       `xml:"summary"`
+	Content   atomText       `xml:"content"`
+	Author    []atomPerson   `xml:"author"`
+	Category  []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// atomText holds an Atom text construct. Plain and html content arrive as
+// character data; xhtml content is markup, so we keep the inner XML for it.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) value() string {
+	if t.Type == "xhtml" {
+		return t.Inner
+	}
+	return t.Text
+}
+
+// alternateLink returns the rel="alternate" link, which Atom treats as the
+// default when rel is omitted, falling back to the first link present.
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+// relLink returns the first link with the given rel, such as "replies".
+func relLink(links []atomLink, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func parseAtom(data []byte) (*RSSFeed, error) {
+	var atomOut atomFeed
+	if err := xml.Unmarshal(data, &atomOut); err != nil {
+		return nil, fmt.Errorf("error unmarshalling atom xml: %w", err)
+	}
+	return atomToRSS(atomOut), nil
+}
+
+// atomToRSS maps a decoded Atom feed onto the RSS shape.
+func atomToRSS(atomOut atomFeed) *RSSFeed {
+	var feedOut RSSFeed
+	feedOut.Channel.Title = atomOut.Title.value()
+	feedOut.Channel.Link = alternateLink(atomOut.Link)
+	feedOut.Channel.Description = atomOut.Subtitle.value()
+
+	for _, entry := range atomOut.Entry {
+		description := entry.Summary.value()
+		if description == "" {
+			description = entry.Content.value()
+		}
+		pubDate := entry.Published
+		if pubDate == "" {
+			pubDate = entry.Updated
+		}
+		item := RSSItem{
+			Title:       entry.Title.value(),
+			Link:        alternateLink(entry.Link),
+			Description: description,
+			PubDate:     pubDate,
+			GUID:        entry.ID,
+			Content:     entry.Content.value(),
+			Comments:    relLink(entry.Link, "replies"),
+		}
+		if len(entry.Author) > 0 {
+			item.Author = entry.Author[0].Name
+		}
+		for _, category := range entry.Category {
+			if category.Label != "" {
+				item.Categories = append(item.Categories, category.Label)
+			} else {
+				item.Categories = append(item.Categories, category.Term)
+			}
+		}
+		for _, link := range entry.Link {
+			if link.Rel == "enclosure" {
+				item.Enclosures = append(item.Enclosures, RSSEnclosure{
+					URL:    link.Href,
+					Type:   link.Type,
+					Length: link.Length,
+				})
+			}
+		}
+		feedOut.Channel.Item = append(feedOut.Channel.Item, item)
+	}
+
+	return &feedOut
+}
@@ -0,0 +1,83 @@
+package rss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeedAtom(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title>Atom Blog</title>
+<subtitle type="html">About &lt;b&gt;things&lt;/b&gt;</subtitle>
+<link rel="self" href="https://a.example/feed.atom"/>
+<link href="https://a.example/"/>
+<entry>
+	<id>urn:uuid:1</id>
+	<title>First</title>
+	<link rel="alternate" href="https://a.example/1"/>
+	<link rel="replies" href="https://a.example/1#comments"/>
+	<link rel="enclosure" type="audio/mpeg" length="1234" href="https://a.example/1.mp3"/>
+	<published>2024-01-02T03:04:05Z</published>
+	<updated>2024-02-02T03:04:05Z</updated>
+	<summary>Short</summary>
+	<content type="html">&lt;p&gt;Long&lt;/p&gt;</content>
+	<author><name>Ann</name></author>
+	<author><name>Bob</name></author>
+	<category term="go" label="Go"/>
+	<category term="rss"/>
+</entry>
+<entry>
+	<id>urn:uuid:2</id>
+	<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Second <em>one</em></div></title>
+	<link rel="self" href="https://a.example/2.atom"/>
+	<updated>2024-03-04T05:06:07Z</updated>
+	<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Body</p></div></content>
+</entry>
+</feed>`
+
+	feed, skipped, err := parseFeed("application/atom+xml", []byte(doc))
+	if err != nil || skipped != 0 {
+		t.Fatalf("parseFeed() skipped = %d, error = %v", skipped, err)
+	}
+
+	channel := feed.Channel
+	if channel.Title != "Atom Blog" || channel.Link != "https://a.example/" || channel.Description != "About <b>things</b>" {
+		t.Errorf("channel = %q, %q, %q", channel.Title, channel.Link, channel.Description)
+	}
+	if len(channel.Item) != 2 {
+		t.Fatalf("parseFeed() returned %d items, want 2", len(channel.Item))
+	}
+
+	first := channel.Item[0]
+	want := RSSItem{
+		Title:       "First",
+		Link:        "https://a.example/1",
+		Description: "Short",
+		PubDate:     "2024-01-02T03:04:05Z",
+		GUID:        "urn:uuid:1",
+		Content:     "<p>Long</p>",
+		Author:      "Ann",
+		Comments:    "https://a.example/1#comments",
+		Categories:  []string{"Go", "rss"},
+		Enclosures:  []RSSEnclosure{{URL: "https://a.example/1.mp3", Type: "audio/mpeg", Length: "1234"}},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("first entry = %+v, want %+v", first, want)
+	}
+
+	second := channel.Item[1]
+	if second.Link != "https://a.example/2.atom" {
+		t.Errorf("second entry Link = %q, want the first link when none is alternate", second.Link)
+	}
+	if second.PubDate != "2024-03-04T05:06:07Z" {
+		t.Errorf("second entry PubDate = %q, want updated when published is missing", second.PubDate)
+	}
+	const body = `<div xmlns="http://www.w3.org/1999/xhtml"><p>Body</p></div>`
+	if second.Description != body || second.Content != body {
+		t.Errorf("second entry Description = %q, Content = %q, want the xhtml markup", second.Description, second.Content)
+	}
+	if second.Title != `<div xmlns="http://www.w3.org/1999/xhtml">Second <em>one</em></div>` {
+		t.Errorf("second entry Title = %q", second.Title)
+	}
+}
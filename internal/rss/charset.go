@@ -0,0 +1,116 @@
+package rss
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+var xmlDeclEncoding = regexp.MustCompile(`^(\s*<\?xml[^>]*?encoding\s*=\s*["'])([A-Za-z0-9._:-]+)(["'])`)
+
+// toUTF8 transcodes a feed to UTF-8. The charset comes from the Content-Type
+// header, then a byte order mark, then the XML declaration, which is rewritten
+// to say UTF-8 so encoding/xml accepts the result. Charsets are looked up by
+// their WHATWG labels, so ISO-8859-1 is read as windows-1252, its superset,
+// as browsers do. A feed in an unknown charset, and any bytes that are still
+// not valid UTF-8, are repaired rather than failing the feed.
+func toUTF8(contentType string, data []byte) []byte {
+	charset := ""
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		charset = params["charset"]
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+		charset = "utf-8"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		data = decodeUTF16(data[2:], false)
+		charset = "utf-8"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		data = decodeUTF16(data[2:], true)
+		charset = "utf-8"
+	}
+
+	if charset == "" {
+		if match := xmlDeclEncoding.FindSubmatch(data); match != nil {
+			charset = string(match[2])
+		}
+	}
+
+	switch label := strings.ToLower(strings.Trim(strings.TrimSpace(charset), `"'`)); label {
+	case "", "utf-8", "utf8":
+	case "utf-16le":
+		data = decodeUTF16(data, false)
+	// XML reads UTF-16 without a byte order mark as big-endian.
+	case "utf-16be", "utf-16":
+		data = decodeUTF16(data, true)
+	default:
+		if encoding, err := htmlindex.Get(label); err == nil {
+			if decoded, err := encoding.NewDecoder().Bytes(data); err == nil {
+				data = decoded
+			}
+		}
+	}
+
+	data = xmlDeclEncoding.ReplaceAll(data, []byte("${1}UTF-8${3}"))
+	return repairUTF8(data)
+}
+
+func decodeUTF16(data []byte, bigEndian bool) []byte {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	out := make([]byte, 0, len(data))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+// repairUTF8 replaces each byte that is not part of valid UTF-8 with its
+// windows-1252 character, the usual cause being a Latin-1 string pasted
+// into a UTF-8 feed, and drops control characters that XML forbids.
+func repairUTF8(data []byte) []byte {
+	clean := true
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if (r == utf8.RuneError && size == 1) || isXMLControl(r) {
+			clean = false
+			break
+		}
+		i += size
+	}
+	if clean {
+		return data
+	}
+
+	out := make([]byte, 0, len(data)+16)
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			out = utf8.AppendRune(out, charmap.Windows1252.DecodeByte(data[i]))
+		case isXMLControl(r):
+		default:
+			out = append(out, data[i:i+size]...)
+		}
+		i += size
+	}
+	return out
+}
+
+func isXMLControl(r rune) bool {
+	return r < 0x20 && r != '\t' && r != '\n' && r != '\r'
+}
@@ -0,0 +1,47 @@
+package rss
+
+import "testing"
+
+func TestToUTF8(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        string
+	}{
+		{"utf-8", "application/rss+xml; charset=utf-8", []byte("<t>Grüße</t>"), "<t>Grüße</t>"},
+		{"utf-8 bom", "", []byte("\xEF\xBB\xBF<t>é</t>"), "<t>é</t>"},
+		{"latin-1 read as windows-1252", "text/xml; charset=ISO-8859-1", []byte("<t>caf\xE9 \x93q\x94</t>"), "<t>café “q”</t>"},
+		{"iso-8859-2", "text/xml; charset=ISO-8859-2", []byte("<t>\xB3\xF3d\xBC</t>"), "<t>łódź</t>"},
+		{"iso-8859-15", "text/xml; charset=iso-8859-15", []byte("<t>\xA4</t>"), "<t>€</t>"},
+		{"windows-1250", "text/xml; charset=windows-1250", []byte("<t>\x8Ae\x9Ea</t>"), "<t>Šeža</t>"},
+		{"koi8-r", "text/xml; charset=KOI8-R", []byte("<t>\xF0\xD2\xC9\xD7\xC5\xD4</t>"), "<t>Привет</t>"},
+		{"declaration", "", []byte(`<?xml version="1.0" encoding="ISO-8859-2"?><t>` + "\xB3</t>"), `<?xml version="1.0" encoding="UTF-8"?><t>ł</t>`},
+		{"header wins over declaration", "text/xml; charset=utf-8", []byte(`<?xml version="1.0" encoding="ISO-8859-2"?><t>ł</t>`), `<?xml version="1.0" encoding="UTF-8"?><t>ł</t>`},
+		{"utf-16le bom", "", []byte("\xFF\xFE<\x00t\x00>\x00\xE9\x00<\x00/\x00t\x00>\x00"), "<t>é</t>"},
+		{"utf-16be bom", "", []byte("\xFE\xFF\x00<\x00t\x00>\x00\xE9\x00<\x00/\x00t\x00>"), "<t>é</t>"},
+		{"unknown charset is repaired", "text/xml; charset=x-made-up", []byte("<t>caf\xE9</t>"), "<t>café</t>"},
+		{"invalid utf-8 is repaired", "text/xml; charset=utf-8", []byte("<t>caf\xE9 ok é</t>"), "<t>café ok é</t>"},
+		{"control characters dropped", "", []byte("<t>a\x01b\x0Bc</t>"), "<t>abc</t>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(toUTF8(tt.contentType, tt.data))
+			if got != tt.want {
+				t.Errorf("toUTF8() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFeedCharsets(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="ISO-8859-2"?><rss version="2.0"><channel><title>Pozna` + "\xF1" + `</title><item><title>` + "\xA3\xF3d\xBC" + `</title></item></channel></rss>`
+	feed, _, err := parseFeed("text/xml", []byte(doc))
+	if err != nil {
+		t.Fatalf("parseFeed() error = %v", err)
+	}
+	if feed.Channel.Title != "Poznań" || len(feed.Channel.Item) != 1 || feed.Channel.Item[0].Title != "Łódź" {
+		t.Errorf("parseFeed() = %q, %+v", feed.Channel.Title, feed.Channel.Item)
+	}
+}
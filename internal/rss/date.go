@@ -0,0 +1,171 @@
+package rss
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DateSource records how a post's publish date was obtained.
+type DateSource string
+
+const (
+	// DateParsed means the feed's value matched a known layout as-is.
+	DateParsed DateSource = "parsed"
+	// DateRepaired means the value only matched after cleaning up a
+	// malformed weekday, month name, zone or spacing.
+	DateRepaired DateSource = "repaired"
+	// DateFetchTime means the value could not be parsed and the time the
+	// feed was fetched was used instead.
+	DateFetchTime DateSource = "fetch_time"
+)
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"Mon, 2 Jan 06 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05",
+	time.RFC822Z,
+	time.RFC822,
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	time.RFC850,
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.UnixDate,
+	time.ANSIC,
+	"2 Jan 2006",
+	"Jan 2, 2006 15:04:05 -0700",
+	"Jan 2, 2006 15:04:05 MST",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"Monday, January 2, 2006",
+	// US-style dates once repairDate has dropped the weekday and commas and
+	// shortened the month name.
+	"Jan 2 2006 15:04:05 -0700",
+	"Jan 2 2006 15:04:05 MST",
+	"Jan 2 2006 15:04 -0700",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006",
+}
+
+// zoneOffsets lists the zone abbreviations feeds commonly use. time.Parse
+// only knows the abbreviations of the local zone and treats any other as
+// UTC, which would shift EST or PDT posts by several hours.
+var zoneOffsets = map[string]int{
+	"UTC":  0,
+	"GMT":  0,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"HST":  -10 * 3600,
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"BST":  1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	"IST":  5*3600 + 1800,
+	"JST":  9 * 3600,
+	"KST":  9 * 3600,
+	"AEST": 10 * 3600,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"NZDT": 13 * 3600,
+}
+
+var (
+	leadingWeekday  = regexp.MustCompile(`(?i)^(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*,?\s+`)
+	trailingComment = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+	colonOffset     = regexp.MustCompile(`(\d\d:\d\d(?::\d\d)?)\s*([+-]\d\d):(\d\d)$`)
+	fullMonthName   = regexp.MustCompile(`(?i)\b(january|february|march|april|june|july|august|september|october|november|december|sept)\b`)
+	utcSuffix       = regexp.MustCompile(`\s(?:UT|Z)$`)
+	repeatedSpace   = regexp.MustCompile(`\s+`)
+)
+
+// ParsePubDate parses a feed item's publish date. Values that match no
+// layout are repaired and retried; if that also fails, fetchedAt is returned
+// so the item is still stored. The DateSource reports which path was taken.
+//
+// The result is always in UTC: posts.published_at has no zone, and the
+// offset of a zoned value would be dropped when it is stored, leaving posts
+// from different zones in their local wall-clock time.
+func ParsePubDate(value string, fetchedAt time.Time) (time.Time, DateSource) {
+	value = strings.TrimSpace(value)
+	if parsed, ok := parseDateLayouts(value); ok {
+		return parsed.UTC(), DateParsed
+	}
+	if parsed, ok := parseDateLayouts(repairDate(value)); ok {
+		return parsed.UTC(), DateRepaired
+	}
+	return fetchedAt.UTC(), DateFetchTime
+}
+
+func parseDateLayouts(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return fixZone(parsed), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fixZone applies the offset for a known zone abbreviation that time.Parse
+// could not resolve on its own.
+func fixZone(t time.Time) time.Time {
+	name, offset := t.Zone()
+	known, ok := zoneOffsets[strings.ToUpper(name)]
+	if !ok || known == offset {
+		return t
+	}
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	return time.Date(year, month, day, hour, min, sec, t.Nanosecond(), time.FixedZone(name, known))
+}
+
+// repairDate cleans up the malformed dates seen in real feeds: misspelled or
+// missing weekdays, full month names, "+01:00" offsets after an RFC 822 time,
+// the RFC 822 "UT" and "Z" zones, trailing "(UTC)" comments and stray
+// whitespace.
+func repairDate(value string) string {
+	value = repeatedSpace.ReplaceAllString(value, " ")
+	value = trailingComment.ReplaceAllString(value, "")
+	value = leadingWeekday.ReplaceAllString(value, "")
+	value = fullMonthName.ReplaceAllStringFunc(value, func(month string) string {
+		return month[:3]
+	})
+	value = colonOffset.ReplaceAllString(value, "$1 $2$3")
+	value = utcSuffix.ReplaceAllString(value, " +0000")
+	value = strings.ReplaceAll(value, ",", "")
+	return strings.TrimSpace(value)
+}
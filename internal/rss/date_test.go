@@ -0,0 +1,68 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePubDate(t *testing.T) {
+	fetchedAt := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Time
+		source DateSource
+	}{
+		{"RFC 1123 numeric zone", "Mon, 02 Jan 2006 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC), DateParsed},
+		{"RFC 1123 GMT", "Mon, 02 Jan 2006 15:04:05 GMT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateParsed},
+		{"RFC 1123 EST", "Mon, 02 Jan 2006 15:04:05 EST", time.Date(2006, 1, 2, 20, 4, 5, 0, time.UTC), DateParsed},
+		{"RFC 1123 PDT", "Tue, 04 Jun 2024 09:30:00 PDT", time.Date(2024, 6, 4, 16, 30, 0, 0, time.UTC), DateParsed},
+		{"RFC 1123 IST", "Tue, 04 Jun 2024 09:30:00 IST", time.Date(2024, 6, 4, 4, 0, 0, 0, time.UTC), DateParsed},
+		{"single digit day", "Mon, 2 Jan 2006 15:04:05 +0100", time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC), DateParsed},
+		{"no seconds", "Mon, 2 Jan 2006 15:04 +0000", time.Date(2006, 1, 2, 15, 4, 0, 0, time.UTC), DateParsed},
+		{"two digit year", "Mon, 2 Jan 06 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateParsed},
+		{"no weekday", "2 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateParsed},
+		{"RFC 3339", "2006-01-02T15:04:05+02:00", time.Date(2006, 1, 2, 13, 4, 5, 0, time.UTC), DateParsed},
+		{"RFC 3339 fraction", "2006-01-02T15:04:05.123Z", time.Date(2006, 1, 2, 15, 4, 5, 123000000, time.UTC), DateParsed},
+		{"ISO without zone", "2006-01-02T15:04:05", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateParsed},
+		{"SQL style", "2006-01-02 15:04:05", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateParsed},
+		{"date only", "2006-01-02", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateParsed},
+		{"surrounding space", "  2006-01-02  ", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateParsed},
+
+		{"misspelled weekday", "Tues, 02 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"full month name", "Mon, 02 January 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"sept", "Sat, 02 Sept 2006 15:04:05 +0000", time.Date(2006, 9, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"colon offset", "Mon, 02 Jan 2006 15:04:05 +01:00", time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC), DateRepaired},
+		{"UT zone", "Mon, 02 Jan 2006 15:04:05 UT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"Z zone", "Mon, 02 Jan 2006 15:04:05 Z", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"zone comment", "Mon, 02 Jan 2006 15:04:05 +0000 (UTC)", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), DateRepaired},
+		{"US date", "January 2, 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateParsed},
+		{"US date short month", "Jan 2, 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateParsed},
+		{"US date with weekday", "Monday, January 2, 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateParsed},
+		{"US date with time", "Jan 2, 2006 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC), DateParsed},
+		{"US date with time and zone name", "Jan 2, 2006 15:04:05 PST", time.Date(2006, 1, 2, 23, 4, 5, 0, time.UTC), DateParsed},
+		{"US date with short weekday", "Mon, Jan 2, 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), DateRepaired},
+		{"US date with full month and time", "January 2, 2006 15:04:05 +0100", time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC), DateRepaired},
+		{"US date with misspelled weekday", "Thurs, Sept 7, 2006 15:04 +0000", time.Date(2006, 9, 7, 15, 4, 0, 0, time.UTC), DateRepaired},
+		{"spelled out with extra spaces", "Tuesday,  02  January 2006 15:04:05   EST", time.Date(2006, 1, 2, 20, 4, 5, 0, time.UTC), DateRepaired},
+
+		{"empty", "", fetchedAt.UTC(), DateFetchTime},
+		{"garbage", "yesterday-ish", fetchedAt.UTC(), DateFetchTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source := ParsePubDate(tt.value, fetchedAt)
+			if source != tt.source {
+				t.Errorf("ParsePubDate(%q) source = %s, want %s", tt.value, source, tt.source)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParsePubDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("ParsePubDate(%q) location = %v, want UTC", tt.value, got.Location())
+			}
+		})
+	}
+}
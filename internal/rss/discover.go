@@ -0,0 +1,161 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// FeedLink is a feed found by Discover. Title is the feed's own title when
+// the page did not name it.
+type FeedLink struct {
+	URL   string
+	Title string
+}
+
+// commonFeedPaths are tried, relative to the site root, when a page does not
+// advertise any feed.
+var commonFeedPaths = []string{"/feed", "/rss.xml", "/atom.xml", "/index.xml"}
+
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/json":      true,
+}
+
+var (
+	linkTagPattern   = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?s)([a-zA-Z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Discover returns the feeds available at pageURL. A URL that is already a
+// feed is returned as is. Otherwise the page's <link rel="alternate"> tags
+// are read, falling back to common feed paths on the same site. Every
+// returned feed has been fetched and parsed successfully.
+func (f *Fetcher) Discover(ctx context.Context, pageURL string) ([]FeedLink, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", pageURL)
+	}
+
+	contentType, page, served, err := f.fetchPage(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if isFeedDocument(contentType, page) {
+		feed, _, err := parseFeed(contentType, page)
+		if err != nil {
+			return nil, err
+		}
+		return []FeedLink{{URL: pageURL, Title: html.UnescapeString(feed.Channel.Title)}}, nil
+	}
+
+	// Links on the page are relative to where it was served from, which
+	// differs from pageURL after a redirect.
+	candidates := pageFeedLinks(served, page)
+	if len(candidates) == 0 {
+		for _, feedPath := range commonFeedPaths {
+			candidate := served.ResolveReference(&url.URL{Path: feedPath})
+			candidates = append(candidates, FeedLink{URL: candidate.String()})
+		}
+	}
+
+	var found []FeedLink
+	for _, candidate := range candidates {
+		fetchRes, err := f.FetchFeed(ctx, candidate.URL, CacheHeaders{})
+		if err != nil || fetchRes.Feed == nil {
+			continue
+		}
+		if candidate.Title == "" {
+			candidate.Title = fetchRes.Feed.Channel.Title
+		}
+		found = append(found, candidate)
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no feed found at %s", pageURL)
+	}
+	return found, nil
+}
+
+// fetchPage reads a page under the same size limits as a feed, since the
+// page may itself be the feed. It also returns the URL the page was served
+// from once any redirects were followed.
+func (f *Fetcher) fetchPage(ctx context.Context, pageURL string) (string, []byte, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error getting page: %w", err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error with http client response: %w", err)
+	}
+	defer drainAndClose(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", nil, nil, fmt.Errorf("unexpected http status: %s", res.Status)
+	}
+
+	page, err := f.readBody(res)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return res.Header.Get("Content-Type"), page, res.Request.URL, nil
+}
+
+// isFeedDocument reports whether data is a feed rather than a web page.
+// parseFeed cannot tell on its own, as it reads any XML as RSS.
+func isFeedDocument(contentType string, data []byte) bool {
+	data = toUTF8(contentType, data)
+	if isJSON(contentType, data) {
+		return isJSONFeed(data)
+	}
+	root, err := rootElement(data)
+	if err != nil {
+		return false
+	}
+	return root == "rss" || root == "feed" || root == "RDF"
+}
+
+// pageFeedLinks returns the feeds advertised by the page's
+// <link rel="alternate"> tags, resolved against the URL the page was served
+// from.
+func pageFeedLinks(base *url.URL, page []byte) []FeedLink {
+	var links []FeedLink
+	seen := make(map[string]bool)
+	for _, tag := range linkTagPattern.FindAll(page, -1) {
+		attrs := make(map[string]string)
+		for _, match := range attributePattern.FindAllSubmatch(tag, -1) {
+			value := string(match[2]) + string(match[3]) + string(match[4])
+			attrs[strings.ToLower(string(match[1]))] = html.UnescapeString(value)
+		}
+
+		isAlternate := false
+		for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+			isAlternate = isAlternate || rel == "alternate"
+		}
+		mediaType := strings.ToLower(strings.TrimSpace(attrs["type"]))
+		if !isAlternate || !feedLinkTypes[mediaType] || attrs["href"] == "" {
+			continue
+		}
+
+		href, err := base.Parse(strings.TrimSpace(attrs["href"]))
+		if err != nil {
+			continue
+		}
+		if seen[href.String()] {
+			continue
+		}
+		seen[href.String()] = true
+		links = append(links, FeedLink{URL: href.String(), Title: attrs["title"]})
+	}
+	return links
+}
@@ -0,0 +1,69 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const discoverTestFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title><item><title>A</title><link>https://blog.example/a</link></item></channel></rss>`
+
+func TestDiscoverAfterRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, "/en/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/en/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/en/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" title="English" href="feed.xml"></head></html>`))
+	})
+	mux.HandleFunc("/en/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(discoverTestFeed))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher, err := NewFetcher(FetcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds, err := fetcher.Discover(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	want := FeedLink{URL: server.URL + "/en/feed.xml", Title: "English"}
+	if len(feeds) != 1 || feeds[0] != want {
+		t.Errorf("Discover() = %+v, want [%+v]", feeds, want)
+	}
+}
+
+func TestDiscoverFeedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(discoverTestFeed))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewFetcher(FetcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds, err := fetcher.Discover(context.Background(), server.URL+"/feed")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	want := FeedLink{URL: server.URL + "/feed", Title: "Blog"}
+	if len(feeds) != 1 || feeds[0] != want {
+		t.Errorf("Discover() = %+v, want [%+v]", feeds, want)
+	}
+}
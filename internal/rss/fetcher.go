@@ -0,0 +1,143 @@
+package rss
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout   = 5 * time.Second
+	defaultUserAgent = "gator"
+)
+
+// FetcherConfig configures the HTTP client a Fetcher uses. Zero values fall
+// back to a 5 second timeout, a 10 MiB body limit, the "gator" User-Agent,
+// the proxy from the environment and the system certificate pool.
+type FetcherConfig struct {
+	Timeout time.Duration
+	// MaxBodyBytes caps the size of a feed body, both as sent and after
+	// decompression. It defaults to 10 MiB.
+	MaxBodyBytes int64
+	ProxyURL     string
+	UserAgent    string
+	// CABundle is a PEM file of extra certificate authorities to trust.
+	CABundle string
+	// Hosts holds extra headers and cookies keyed by lower-case host name.
+	Hosts map[string]HostConfig
+}
+
+// HostConfig is sent with every request to one host.
+type HostConfig struct {
+	Headers map[string]string
+	Cookies map[string]string
+}
+
+// Fetcher downloads feeds and web pages through one HTTP client, so that
+// connections are reused across fetches. It is safe for concurrent use.
+type Fetcher struct {
+	client       *http.Client
+	maxBodyBytes int64
+}
+
+// NewFetcher builds a Fetcher from cfg.
+func NewFetcher(cfg FetcherConfig) (*Fetcher, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 4
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxBodyBytes := cfg.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	hosts := make(map[string]HostConfig, len(cfg.Hosts))
+	for host, hostCfg := range cfg.Hosts {
+		hosts[strings.ToLower(host)] = hostCfg
+	}
+
+	return &Fetcher{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &hostTransport{
+				base:      transport,
+				userAgent: userAgent,
+				hosts:     hosts,
+			},
+		},
+		maxBodyBytes: maxBodyBytes,
+	}, nil
+}
+
+// Timeout returns the limit on each feed or page request.
+func (f *Fetcher) Timeout() time.Duration {
+	return f.client.Timeout
+}
+
+// DownloadClient returns a client sharing the fetcher's connections, proxy,
+// TLS and header settings but without its timeout, which would cut off large
+// downloads.
+func (f *Fetcher) DownloadClient() *http.Client {
+	client := *f.client
+	client.Timeout = 0
+	return &client
+}
+
+// hostTransport adds the User-Agent and per-host headers and cookies to each
+// request, including every hop of a redirect.
+type hostTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	hosts     map[string]HostConfig
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	if hostCfg, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; ok {
+		for name, value := range hostCfg.Headers {
+			req.Header.Set(name, value)
+		}
+		for name, value := range hostCfg.Cookies {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,144 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonFeedVersionPrefix starts the version URL every JSON Feed declares,
+// which tells a feed apart from any other JSON document.
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            jsonFeedID `json:"id"`
+	URL           string     `json:"url"`
+	ExternalURL   string     `json:"external_url"`
+	Title         string     `json:"title"`
+	ContentHTML   string     `json:"content_html"`
+	ContentText   string     `json:"content_text"`
+	Summary       string     `json:"summary"`
+	DatePublished string     `json:"date_published"`
+	DateModified  string     `json:"date_modified"`
+	// Author is JSON Feed 1.0; 1.1 replaced it with Authors.
+	Author      *jsonFeedAuthor      `json:"author"`
+	Authors     []jsonFeedAuthor     `json:"authors"`
+	Tags        []string             `json:"tags"`
+	Attachments []jsonFeedAttachment `json:"attachments"`
+}
+
+// jsonFeedID is an item id. The spec calls for a string but asks readers to
+// accept a number and use it as a string, as some publishers send one.
+type jsonFeedID string
+
+func (id *jsonFeedID) UnmarshalJSON(data []byte) error {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case string:
+		*id = jsonFeedID(v)
+	case json.Number:
+		*id = jsonFeedID(v.String())
+	case nil:
+		*id = ""
+	default:
+		return fmt.Errorf("json feed item id must be a string or number, got %s", data)
+	}
+	return nil
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+func parseJSONFeed(data []byte) (*RSSFeed, error) {
+	var jsonOut jsonFeed
+	if err := json.Unmarshal(data, &jsonOut); err != nil {
+		return nil, fmt.Errorf("error unmarshalling json feed: %w", err)
+	}
+	if !strings.HasPrefix(jsonOut.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("json document is not a json feed: version %q", jsonOut.Version)
+	}
+
+	var feedOut RSSFeed
+	feedOut.Channel.Title = jsonOut.Title
+	feedOut.Channel.Link = jsonOut.HomePageURL
+	feedOut.Channel.Description = jsonOut.Description
+
+	for _, item := range jsonOut.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+		description := item.ContentHTML
+		if description == "" {
+			description = item.ContentText
+		}
+		if description == "" {
+			description = item.Summary
+		}
+		pubDate := item.DatePublished
+		if pubDate == "" {
+			pubDate = item.DateModified
+		}
+		itemOut := RSSItem{
+			Title:       item.Title,
+			Link:        link,
+			Description: description,
+			PubDate:     pubDate,
+			GUID:        string(item.ID),
+			Content:     item.ContentHTML,
+			Categories:  item.Tags,
+		}
+		if len(item.Authors) > 0 {
+			itemOut.Author = item.Authors[0].Name
+		} else if item.Author != nil {
+			itemOut.Author = item.Author.Name
+		}
+		for _, attachment := range item.Attachments {
+			length := ""
+			if attachment.SizeInBytes > 0 {
+				length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			itemOut.Enclosures = append(itemOut.Enclosures, RSSEnclosure{
+				URL:    attachment.URL,
+				Type:   attachment.MimeType,
+				Length: length,
+			})
+		}
+		feedOut.Channel.Item = append(feedOut.Channel.Item, itemOut)
+	}
+
+	return &feedOut, nil
+}
+
+// isJSONFeed reports whether a JSON document declares a JSON Feed version.
+func isJSONFeed(data []byte) bool {
+	var probe struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return strings.HasPrefix(probe.Version, jsonFeedVersionPrefix)
+}
@@ -0,0 +1,77 @@
+package rss
+
+import "testing"
+
+func TestParseJSONFeed(t *testing.T) {
+	doc := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "J",
+		"home_page_url": "https://j.example/",
+		"items": [
+			{"id": "a", "url": "https://j.example/a", "title": "String id", "content_html": "<p>A</p>", "authors": [{"name": "Ann"}]},
+			{"id": 123, "external_url": "https://x.example/b", "title": "Number id", "summary": "B", "author": {"name": "Bob"}},
+			{"id": 1.5e3, "url": "https://j.example/c", "content_text": "C"},
+			{"id": null, "url": "https://j.example/d"}
+		]
+	}`
+
+	feed, _, err := parseFeed("application/feed+json", []byte(doc))
+	if err != nil {
+		t.Fatalf("parseFeed() error = %v", err)
+	}
+	if feed.Channel.Title != "J" || feed.Channel.Link != "https://j.example/" {
+		t.Errorf("channel = %q, %q", feed.Channel.Title, feed.Channel.Link)
+	}
+
+	want := []RSSItem{
+		{GUID: "a", Link: "https://j.example/a", Title: "String id", Description: "<p>A</p>", Content: "<p>A</p>", Author: "Ann"},
+		{GUID: "123", Link: "https://x.example/b", Title: "Number id", Description: "B", Author: "Bob"},
+		{GUID: "1.5e3", Link: "https://j.example/c", Description: "C"},
+		{GUID: "", Link: "https://j.example/d"},
+	}
+	if len(feed.Channel.Item) != len(want) {
+		t.Fatalf("parseFeed() returned %d items, want %d", len(feed.Channel.Item), len(want))
+	}
+	for i, w := range want {
+		got := feed.Channel.Item[i]
+		if got.GUID != w.GUID || got.Link != w.Link || got.Title != w.Title || got.Description != w.Description || got.Content != w.Content || got.Author != w.Author {
+			t.Errorf("item %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseFeedRejectsOtherJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		doc         string
+		// isFeed is whether discovery takes the document for a feed.
+		isFeed bool
+	}{
+		{"api response", "application/json", `{"items": [{"id": 1}], "count": 1}`, false},
+		{"no content type", "", `{"status": "ok"}`, false},
+		{"wrong version", "application/feed+json", `{"version": "1.1", "items": []}`, false},
+		{"array", "application/json", `[{"version": "https://jsonfeed.org/version/1.1"}]`, false},
+		{"object id", "application/feed+json", `{"version": "https://jsonfeed.org/version/1", "items": [{"id": {"x": 1}}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if feed, _, err := parseFeed(tt.contentType, []byte(tt.doc)); err == nil {
+				t.Errorf("parseFeed() = %+v, want an error", feed)
+			}
+			if got := isFeedDocument(tt.contentType, []byte(tt.doc)); got != tt.isFeed {
+				t.Errorf("isFeedDocument() = %v, want %v", got, tt.isFeed)
+			}
+		})
+	}
+}
+
+func TestIsFeedDocumentJSON(t *testing.T) {
+	doc := []byte(`{"version": "https://jsonfeed.org/version/1", "title": "J", "items": []}`)
+	for _, contentType := range []string{"application/feed+json", "application/json", "text/plain"} {
+		if !isFeedDocument(contentType, doc) {
+			t.Errorf("isFeedDocument(%q) = false, want true", contentType)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+)
+
+// feedAutoClose is xml.HTMLAutoClose without link, which in RSS is an
+// element with content rather than an empty HTML tag.
+var feedAutoClose = func() []string {
+	var names []string
+	for _, name := range xml.HTMLAutoClose {
+		if name != "link" {
+			names = append(names, name)
+		}
+	}
+	return names
+}()
+
+var (
+	rssItemPattern   = regexp.MustCompile(`(?s)<item\b.*?</item\s*>`)
+	atomEntryPattern = regexp.MustCompile(`(?s)<entry\b.*?</entry\s*>`)
+)
+
+// parseLenient salvages an RSS or Atom document that failed strict parsing.
+// The decoder accepts bare ampersands, HTML entities such as &nbsp; and
+// unclosed HTML tags, and each item is decoded on its own so that one broken
+// item, for instance with an unterminated CDATA section, only loses that
+// item. It returns the number of items that could not be read.
+func parseLenient(data []byte, root string, strictErr error) (*RSSFeed, int, error) {
+	rootTag, rootName, ok := rootStartTag(data)
+	if !ok || (root != "rss" && root != "feed") {
+		return nil, 0, strictErr
+	}
+
+	pattern := rssItemPattern
+	if root == "feed" {
+		pattern = atomEntryPattern
+	}
+	chunks := pattern.FindAll(data, -1)
+	header := pattern.ReplaceAll(data, nil)
+
+	// Items are wrapped in the original root start tag so that the
+	// namespace prefixes it declares still resolve.
+	var feedOut *RSSFeed
+	var items []RSSItem
+	skipped := 0
+	if root == "feed" {
+		var atomOut atomFeed
+		if err := unmarshalLenient(header, &atomOut); err != nil {
+			atomOut = atomFeed{}
+		}
+		feedOut = atomToRSS(atomOut)
+		for _, chunk := range chunks {
+			var entryOut atomFeed
+			wrapped := wrapChunk(rootTag, chunk, "", "</"+rootName+">")
+			if err := unmarshalLenient(wrapped, &entryOut); err != nil || len(entryOut.Entry) != 1 {
+				skipped++
+				continue
+			}
+			items = append(items, atomToRSS(entryOut).Channel.Item...)
+		}
+	} else {
+		feedOut = &RSSFeed{}
+		if err := unmarshalLenient(header, feedOut); err != nil {
+			feedOut = &RSSFeed{}
+		}
+		for _, chunk := range chunks {
+			var itemOut RSSFeed
+			wrapped := wrapChunk(rootTag, chunk, "<channel>", "</channel></"+rootName+">")
+			if err := unmarshalLenient(wrapped, &itemOut); err != nil || len(itemOut.Channel.Item) != 1 {
+				skipped++
+				continue
+			}
+			items = append(items, itemOut.Channel.Item[0])
+		}
+	}
+
+	if len(items) == 0 && feedOut.Channel.Title == "" {
+		return nil, skipped, strictErr
+	}
+	feedOut.Channel.Item = items
+	return feedOut, skipped, nil
+}
+
+func unmarshalLenient(data []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = feedAutoClose
+	decoder.Entity = xml.HTMLEntity
+	return decoder.Decode(v)
+}
+
+// rootStartTag returns the document's root start tag as written, with its
+// namespace declarations, and the root's qualified name.
+func rootStartTag(data []byte) ([]byte, string, bool) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		before := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nil, "", false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			name := start.Name.Local
+			if start.Name.Space != "" {
+				name = start.Name.Space + ":" + name
+			}
+			return bytes.TrimSpace(data[before:decoder.InputOffset()]), name, true
+		}
+	}
+}
+
+func wrapChunk(rootTag, chunk []byte, open, closing string) []byte {
+	wrapped := make([]byte, 0, len(rootTag)+len(open)+len(chunk)+len(closing))
+	wrapped = append(wrapped, rootTag...)
+	wrapped = append(wrapped, open...)
+	wrapped = append(wrapped, chunk...)
+	wrapped = append(wrapped, closing...)
+	return wrapped
+}
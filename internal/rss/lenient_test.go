@@ -0,0 +1,103 @@
+package rss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeedLenient(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		wantTitle   string
+		wantItems   []string
+		wantCreator string
+		wantSkipped int
+		wantErr     bool
+	}{
+		{
+			name: "bare ampersand and html entity",
+			doc: `<rss version="2.0"><channel><title>Tom & Jerry</title>
+<item><title>Caf&eacute;&nbsp;news</title><link>https://a.example/1</link></item>
+</channel></rss>`,
+			wantTitle: "Tom & Jerry",
+			wantItems: []string{"Café news"},
+		},
+		{
+			name: "unterminated cdata loses one item",
+			doc: `<rss version="2.0"><channel><title>Blog</title>
+<item><title>One</title></item>
+<item><title><![CDATA[Two</title></item>
+<item><title>Three</title></item>
+</channel></rss>`,
+			wantTitle:   "Blog",
+			wantItems:   []string{"One", "Three"},
+			wantSkipped: 1,
+		},
+		{
+			name: "unclosed html tag in an item",
+			doc: `<rss version="2.0"><channel><title>Blog</title>
+<item><title>One</title><description>line<br>break</description></item>
+</channel></rss>`,
+			wantTitle: "Blog",
+			wantItems: []string{"One"},
+		},
+		{
+			name: "namespace declared on the root",
+			doc: `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>Blog & co</title>
+<item><title>One</title><dc:creator>Ann</dc:creator></item>
+</channel></rss>`,
+			wantTitle:   "Blog & co",
+			wantItems:   []string{"One"},
+			wantCreator: "Ann",
+		},
+		{
+			name: "atom",
+			doc: `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom & Co</title>
+<entry><title>One &mdash; two</title></entry>
+<entry><title><![CDATA[Broken</title></entry>
+</feed>`,
+			wantTitle:   "Atom & Co",
+			wantItems:   []string{"One — two"},
+			wantSkipped: 1,
+		},
+		{
+			name:    "nothing readable",
+			doc:     `<rss version="2.0"><channel><item><title><![CDATA[x</title></item></channel></rss>`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown root",
+			doc:     `<html><body>&nbsp;</body></html>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, skipped, err := parseFeed("application/xml", []byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if feed.Channel.Title != tt.wantTitle {
+				t.Errorf("parseFeed() title = %q, want %q", feed.Channel.Title, tt.wantTitle)
+			}
+			var titles []string
+			for _, item := range feed.Channel.Item {
+				titles = append(titles, item.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantItems) {
+				t.Errorf("parseFeed() items = %q, want %q", titles, tt.wantItems)
+			}
+			if tt.wantCreator != "" && feed.Channel.Item[0].Creator != tt.wantCreator {
+				t.Errorf("parseFeed() creator = %q, want %q", feed.Channel.Item[0].Creator, tt.wantCreator)
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("parseFeed() skipped = %d, want %d", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
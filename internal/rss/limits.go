@@ -0,0 +1,132 @@
+package rss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultMaxBodyBytes = 10 << 20
+	// maxXMLDepth is far deeper than any real feed nests its elements.
+	maxXMLDepth = 100
+)
+
+// readBody reads a response body, decompressing gzip and deflate, and fails
+// once either the body as sent or the decompressed document passes the
+// configured maximum size, so a small compressed bomb cannot expand into
+// memory.
+func (f *Fetcher) readBody(res *http.Response) ([]byte, error) {
+	if res.ContentLength > f.maxBodyBytes {
+		return nil, fmt.Errorf("response body of %d bytes exceeds limit of %d bytes", res.ContentLength, f.maxBodyBytes)
+	}
+
+	raw := &limitedReader{r: res.Body, remaining: f.maxBodyBytes}
+	body, err := decompress(res.Header.Get("Content-Encoding"), raw)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, f.maxBodyBytes+1))
+	if errors.Is(err, errBodyTooLarge) {
+		return data, fmt.Errorf("response body exceeds limit of %d bytes", f.maxBodyBytes)
+	}
+	if err != nil {
+		return data, fmt.Errorf("error reading http response: %w", err)
+	}
+	if int64(len(data)) > f.maxBodyBytes {
+		return data, fmt.Errorf("decompressed response body exceeds limit of %d bytes", f.maxBodyBytes)
+	}
+	return data, nil
+}
+
+var errBodyTooLarge = errors.New("body too large")
+
+// limitedReader is io.LimitReader that reports running out as an error
+// instead of a silent EOF, so a truncated body is not parsed as complete.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Anything further means the body is over the limit.
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, errBodyTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func decompress(contentEncoding string, body io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing gzip body: %w", err)
+		}
+		return reader, nil
+	case "deflate":
+		// HTTP deflate is meant to be zlib-wrapped, but some servers send raw
+		// deflate data, told apart by the zlib header.
+		head := make([]byte, 2)
+		n, _ := io.ReadFull(body, head)
+		body = io.MultiReader(bytes.NewReader(head[:n]), body)
+		if n == 2 && head[0]&0x0f == 8 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0 {
+			reader, err := zlib.NewReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("error decompressing deflate body: %w", err)
+			}
+			return reader, nil
+		}
+		return flate.NewReader(body), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+}
+
+// checkXMLLimits rejects documents nested deeper than any real feed. Entity
+// declarations in a DTD are let through: encoding/xml never expands them, so
+// they cannot blow up, and the lenient parser resolves the HTML entities
+// that feeds declare this way.
+func checkXMLLimits(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	depth := 0
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// Syntax errors are reported by the parser proper.
+			return nil
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth > maxXMLDepth {
+				return fmt.Errorf("xml nested deeper than %d elements", maxXMLDepth)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
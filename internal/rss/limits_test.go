@@ -0,0 +1,119 @@
+package rss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	const limit = 1000
+	small := strings.Repeat("a", 600)
+	large := strings.Repeat("a", 1500)
+
+	compress := func(encoding, data string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "zlib":
+			w = zlib.NewWriter(&buf)
+		case "deflate":
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		}
+		w.Write([]byte(data))
+		w.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name            string
+		contentEncoding string
+		contentLength   int64
+		body            []byte
+		want            string
+		wantErr         bool
+	}{
+		{"plain", "", -1, []byte(small), small, false},
+		{"exactly the limit", "", -1, []byte(large[:limit]), large[:limit], false},
+		{"declared length over limit", "", 1500, []byte(large), "", true},
+		{"undeclared length over limit", "", -1, []byte(large), "", true},
+		{"gzip", "gzip", -1, compress("gzip", small), small, false},
+		{"x-gzip", "x-gzip", -1, compress("gzip", small), small, false},
+		{"gzip expanding past limit", "gzip", -1, compress("gzip", large), "", true},
+		{"zlib deflate", "deflate", -1, compress("zlib", small), small, false},
+		{"raw deflate", "deflate", -1, compress("deflate", small), small, false},
+		{"raw deflate expanding past limit", "deflate", -1, compress("deflate", large), "", true},
+		{"corrupt gzip", "gzip", -1, []byte("not gzip"), "", true},
+		{"unknown encoding", "br", -1, []byte(small), "", true},
+	}
+
+	fetcher := &Fetcher{maxBodyBytes: limit}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				Header:        http.Header{},
+				ContentLength: tt.contentLength,
+				Body:          io.NopCloser(bytes.NewReader(tt.body)),
+			}
+			if tt.contentEncoding != "" {
+				res.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+			got, err := fetcher.readBody(res)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("readBody() = %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCheckXMLLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("<a>", depth) + strings.Repeat("</a>", depth)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{"feed", discoverTestFeed, false},
+		{"at depth limit", nested(maxXMLDepth), false},
+		{"past depth limit", nested(maxXMLDepth + 1), true},
+		{"entity declaration", `<!DOCTYPE rss [<!ENTITY nbsp "&#160;">]><rss/>`, false},
+		{"syntax error left to the parser", "<rss><channel></rss>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkXMLLimits([]byte(tt.doc)); (err != nil) != tt.wantErr {
+				t.Errorf("checkXMLLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFeedDeclaredEntities(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<!DOCTYPE rss [<!ENTITY nbsp "&#160;"><!ENTITY mdash "&#8212;">]>
+<rss version="2.0"><channel><title>Entities</title>
+<item><title>a&nbsp;b&mdash;c</title><link>https://e.example/1</link></item>
+</channel></rss>`
+
+	feed, _, err := parseFeed("application/rss+xml", []byte(doc))
+	if err != nil {
+		t.Fatalf("parseFeed() error = %v", err)
+	}
+	if len(feed.Channel.Item) != 1 || feed.Channel.Item[0].Title != "a\u00a0b—c" {
+		t.Errorf("parseFeed() items = %+v", feed.Channel.Item)
+	}
+}
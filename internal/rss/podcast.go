@@ -0,0 +1,51 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ItunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// PodcastEpisode is the itunes:* metadata of a podcast item. Zero values mean
+// the feed did not supply the field.
+type PodcastEpisode struct {
+	Duration time.Duration
+	Episode  int
+	Season   int
+	ImageURL string
+}
+
+// PodcastEpisode reads the item's itunes:duration, itunes:episode,
+// itunes:season and itunes:image.
+func (item RSSItem) PodcastEpisode() PodcastEpisode {
+	episode, _ := strconv.Atoi(strings.TrimSpace(item.ItunesEpisode))
+	season, _ := strconv.Atoi(strings.TrimSpace(item.ItunesSeason))
+	return PodcastEpisode{
+		Duration: parseItunesDuration(item.ItunesDuration),
+		Episode:  episode,
+		Season:   season,
+		ImageURL: strings.TrimSpace(item.ItunesImage.Href),
+	}
+}
+
+// parseItunesDuration accepts the forms itunes:duration is published in:
+// plain seconds, MM:SS and HH:MM:SS.
+func parseItunesDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var seconds int
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second
+}
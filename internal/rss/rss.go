@@ -1,12 +1,15 @@
 package rss
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
 	"fmt"
 	"html"
 	"io"
+	"mime"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -15,21 +18,133 @@ type RSSFeed struct {
 		Title       string    `xml:"title"`
 		Link        string    `xml:"link"`
 		Description string    `xml:"description"`
+		TTL         string    `xml:"ttl"`
+		SkipHours   []string  `xml:"skipHours>hour"`
+		SkipDays    []string  `xml:"skipDays>day"`
 		Item        []RSSItem `xml:"item"`
 	} `xml:"channel"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	PubDate     string         `xml:"pubDate"`
+	GUID        string         `xml:"guid"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Creator     string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Author      string         `xml:"-"`
+	Categories  []string       `xml:"category"`
+	Comments    string         `xml:"-"`
+	Enclosures  []RSSEnclosure `xml:"enclosure"`
+	// iTunes podcast metadata, read through PodcastEpisode.
+	ItunesDuration string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ItunesEpisode  string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ItunesSeason   string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	ItunesImage    ItunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	var feedOut RSSFeed
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+// rssElement is an element decoded along with its namespace, so that RSS
+// elements can be told apart from extensions sharing their local name.
+type rssElement struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// UnmarshalXML decodes an item, reading author and comments only from the
+// RSS elements themselves. encoding/xml matches an unqualified name in any
+// namespace, so itunes:author or slash:comments, which holds a comment
+// count, would otherwise overwrite them.
+func (item *RSSItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainItem RSSItem
+	var decoded struct {
+		plainItem
+		Authors  []rssElement `xml:"author"`
+		Comments []rssElement `xml:"comments"`
+	}
+	if err := d.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	*item = RSSItem(decoded.plainItem)
+	item.Author = unqualifiedValue(decoded.Authors)
+	item.Comments = unqualifiedValue(decoded.Comments)
+	return nil
+}
+
+// unqualifiedValue returns the text of the first element with no namespace.
+func unqualifiedValue(elements []rssElement) string {
+	for _, element := range elements {
+		if element.XMLName.Space == "" {
+			return element.Value
+		}
+	}
+	return ""
+}
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// AuthorName returns the item's dc:creator, which holds a display name,
+// falling back to the RSS author element, which is usually an email address.
+func (item RSSItem) AuthorName() string {
+	if creator := strings.TrimSpace(item.Creator); creator != "" {
+		return creator
+	}
+	return strings.TrimSpace(item.Author)
+}
+
+// CacheHeaders are the validators a server sent with a feed, replayed on the
+// next fetch as If-None-Match and If-Modified-Since.
+type CacheHeaders struct {
+	ETag         string
+	LastModified string
+}
+
+// FetchResult is a fetched feed along with the response details callers
+// persist between fetches. Feed is nil when NotModified is set.
+type FetchResult struct {
+	Feed        *RSSFeed
+	NotModified bool
+	Cache       CacheHeaders
+	StatusCode  int
+	Bytes       int
+	// NotBefore is the earliest time the server asked to be fetched again,
+	// from Retry-After, Cache-Control or Expires. It is zero without a hint.
+	NotBefore time.Time
+	// SkippedItems counts items dropped from a malformed feed that was
+	// otherwise salvaged by lenient parsing.
+	SkippedItems int
+	// MovedTo is the URL the feed was reached at when every redirect on the
+	// way was permanent (301 or 308). It is empty when there was none.
+	MovedTo string
+}
+
+// maxRedirects caps redirect chains so that loops fail quickly.
+const maxRedirects = 5
+
+// FetchFeed downloads and parses a feed. Once the server has responded the
+// returned FetchResult is non-nil even when err is set, so callers can record
+// the status code and size of failed fetches.
+func (f *Fetcher) FetchFeed(ctx context.Context, feedURL string, cache CacheHeaders) (*FetchResult, error) {
+	var movedTo string
+	permanent := true
+	// A copy of the client shares its transport, and so its connections,
+	// while tracking this fetch's redirects.
+	client := *f.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		code := req.Response.StatusCode
+		permanent = permanent && (code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect)
+		movedTo = ""
+		if permanent {
+			movedTo = req.URL.String()
+		}
+		return nil
 	}
 
 	if ctx == nil {
@@ -41,21 +156,58 @@ func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 		return nil, fmt.Errorf("error getting feedURL: %w", geterr)
 	}
 
-	req.Header.Set("User-Agent", "gator")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, */*;q=0.8")
+	// Asking for compression ourselves turns off the transport's transparent
+	// gzip, so readBody can limit the decompressed size.
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
 
 	res, reserr := client.Do(req)
 	if reserr != nil {
 		return nil, fmt.Errorf("error with http client response: %w", reserr)
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
+
+	result := &FetchResult{
+		MovedTo:    movedTo,
+		StatusCode: res.StatusCode,
+		NotBefore:  notBefore(res, time.Now()),
+		Cache: CacheHeaders{
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+		},
+	}
+
+	if res.StatusCode == http.StatusNotModified {
+		// A 304 may omit validators that are still current.
+		if result.Cache.ETag == "" {
+			result.Cache.ETag = cache.ETag
+		}
+		if result.Cache.LastModified == "" {
+			result.Cache.LastModified = cache.LastModified
+		}
+		result.NotModified = true
+		return result, nil
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return result, fmt.Errorf("unexpected http status: %s", res.Status)
+	}
 
-	xmlData, readerr := io.ReadAll(res.Body)
+	xmlData, readerr := f.readBody(res)
+	result.Bytes = len(xmlData)
 	if readerr != nil {
-		return nil, fmt.Errorf("error reading http response: %w", readerr)
+		return result, readerr
 	}
-	err := xml.Unmarshal(xmlData, &feedOut)
+	feedOut, skipped, err := parseFeed(res.Header.Get("Content-Type"), xmlData)
+	result.SkippedItems = skipped
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling xml: %w", err)
+		return result, err
 	}
 	feedOut.Channel.Description = html.UnescapeString(feedOut.Channel.Description)
 	feedOut.Channel.Title = html.UnescapeString(feedOut.Channel.Title)
@@ -65,5 +217,75 @@ func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 		feedOut.Channel.Item[i].Description = html.UnescapeString(feedOut.Channel.Item[i].Description)
 	}
 
-	return &feedOut, nil
+	result.Feed = feedOut
+	return result, nil
+}
+
+// drainAndClose reads what is left of a small response body before closing
+// it, so the connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
+// parseFeed decodes the document as JSON Feed, Atom or RSS, always returning
+// the RSS shape used by the rest of the program. JSON is recognised by its
+// Content-Type or a leading brace and must declare a JSON Feed version; XML
+// formats are recognised by their root element. XML that fails to parse is
+// retried leniently, and the number of items that had to be skipped is
+// returned.
+func parseFeed(contentType string, data []byte) (*RSSFeed, int, error) {
+	data = toUTF8(contentType, data)
+
+	if isJSON(contentType, data) {
+		feedOut, err := parseJSONFeed(data)
+		return feedOut, 0, err
+	}
+
+	if err := checkXMLLimits(data); err != nil {
+		return nil, 0, err
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error unmarshalling xml: %w", err)
+	}
+
+	if root == "feed" {
+		feedOut, err := parseAtom(data)
+		if err != nil {
+			return parseLenient(data, root, err)
+		}
+		return feedOut, 0, nil
+	}
+
+	var feedOut RSSFeed
+	if err := xml.Unmarshal(data, &feedOut); err != nil {
+		return parseLenient(data, root, fmt.Errorf("error unmarshalling xml: %w", err))
+	}
+	return &feedOut, 0, nil
+}
+
+// isJSON reports whether a document is JSON rather than XML, going by its
+// Content-Type or a leading brace. parseJSONFeed checks that it is a feed.
+func isJSON(contentType string, data []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/feed+json" || mediaType == "application/json" {
+		return true
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func rootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
 }
@@ -0,0 +1,137 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseFeedNamespacedElements(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:slash="http://purl.org/rss/1.0/modules/slash/" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>T</title>
+<item>
+	<title>WordPress</title>
+	<comments>https://a.example/1#respond</comments>
+	<slash:comments>7</slash:comments>
+</item>
+<item>
+	<title>Count first</title>
+	<slash:comments>3</slash:comments>
+	<comments>https://a.example/2#comments</comments>
+</item>
+<item>
+	<title>Podcast</title>
+	<author>host@example.com (Host)</author>
+	<itunes:author>The Show</itunes:author>
+</item>
+<item>
+	<title>Only itunes</title>
+	<itunes:author>The Show</itunes:author>
+	<slash:comments>2</slash:comments>
+</item>
+<item>
+	<title>Creator</title>
+	<dc:creator>Jane</dc:creator>
+	<author>jane@example.com</author>
+</item>
+</channel></rss>`
+
+	feed, skipped, err := parseFeed("application/rss+xml", []byte(doc))
+	if err != nil || skipped != 0 {
+		t.Fatalf("parseFeed() skipped = %d, error = %v", skipped, err)
+	}
+
+	want := []struct {
+		author   string
+		comments string
+		name     string
+	}{
+		{"", "https://a.example/1#respond", ""},
+		{"", "https://a.example/2#comments", ""},
+		{"host@example.com (Host)", "", "host@example.com (Host)"},
+		{"", "", ""},
+		{"jane@example.com", "", "Jane"},
+	}
+	if len(feed.Channel.Item) != len(want) {
+		t.Fatalf("parseFeed() returned %d items, want %d", len(feed.Channel.Item), len(want))
+	}
+	for i, w := range want {
+		item := feed.Channel.Item[i]
+		if item.Author != w.author || item.Comments != w.comments || item.AuthorName() != w.name {
+			t.Errorf("item %q: Author = %q, Comments = %q, AuthorName() = %q; want %q, %q, %q",
+				item.Title, item.Author, item.Comments, item.AuthorName(), w.author, w.comments, w.name)
+		}
+	}
+}
+
+func TestFetchFeedRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(discoverTestFeed))
+	})
+	mux.Handle("/moved", http.RedirectHandler("/feed", http.StatusMovedPermanently))
+	mux.Handle("/permanent", http.RedirectHandler("/feed", http.StatusPermanentRedirect))
+	mux.Handle("/found", http.RedirectHandler("/feed", http.StatusFound))
+	mux.Handle("/temporary", http.RedirectHandler("/feed", http.StatusTemporaryRedirect))
+	mux.Handle("/moved-then-found", http.RedirectHandler("/found", http.StatusMovedPermanently))
+	mux.Handle("/found-then-moved", http.RedirectHandler("/moved", http.StatusFound))
+	mux.Handle("/loop", http.RedirectHandler("/loop", http.StatusMovedPermanently))
+	mux.HandleFunc("/chain/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/chain/"))
+		if n == 0 {
+			http.Redirect(w, r, "/feed", http.StatusMovedPermanently)
+			return
+		}
+		http.Redirect(w, r, "/chain/"+strconv.Itoa(n-1), http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path        string
+		wantMovedTo string
+		wantErr     bool
+	}{
+		{"/feed", "", false},
+		{"/moved", "/feed", false},
+		{"/permanent", "/feed", false},
+		{"/found", "", false},
+		{"/temporary", "", false},
+		{"/moved-then-found", "", false},
+		{"/found-then-moved", "", false},
+		{"/chain/" + strconv.Itoa(maxRedirects-1), "/feed", false},
+		{"/chain/" + strconv.Itoa(maxRedirects), "", true},
+		{"/loop", "", true},
+	}
+
+	fetcher, err := NewFetcher(FetcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			result, err := fetcher.FetchFeed(context.Background(), server.URL+tt.path, CacheHeaders{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			wantMovedTo := ""
+			if tt.wantMovedTo != "" {
+				wantMovedTo = server.URL + tt.wantMovedTo
+			}
+			if result.MovedTo != wantMovedTo {
+				t.Errorf("FetchFeed() MovedTo = %q, want %q", result.MovedTo, wantMovedTo)
+			}
+			if result.Feed == nil || result.Feed.Channel.Title != "Blog" {
+				t.Errorf("FetchFeed() Feed = %+v", result.Feed)
+			}
+		})
+	}
+}
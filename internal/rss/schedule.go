@@ -0,0 +1,131 @@
+package rss
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxNotBefore caps how far Cache-Control, Expires and Retry-After may push a
+// fetch out, so a misconfigured server cannot stall a feed indefinitely.
+const maxNotBefore = 24 * time.Hour
+
+// Schedule is the publisher's guidance on when a feed may be fetched again,
+// taken from the RSS channel's ttl, skipHours and skipDays elements.
+type Schedule struct {
+	TTLMinutes int
+	SkipHours  []int
+	SkipDays   []time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Schedule returns the channel's ttl, skipHours and skipDays, ignoring any
+// values that are out of range or malformed.
+func (f *RSSFeed) Schedule() Schedule {
+	var sch Schedule
+	if ttl, err := strconv.Atoi(strings.TrimSpace(f.Channel.TTL)); err == nil && ttl > 0 {
+		sch.TTLMinutes = ttl
+	}
+	for _, value := range f.Channel.SkipHours {
+		if hour, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && hour >= 0 && hour <= 24 {
+			// RSS allows both 0 and 24 for midnight.
+			sch.SkipHours = append(sch.SkipHours, hour%24)
+		}
+	}
+	for _, value := range f.Channel.SkipDays {
+		if day, ok := weekdays[strings.ToLower(strings.TrimSpace(value))]; ok {
+			sch.SkipDays = append(sch.SkipDays, day)
+		}
+	}
+	return sch
+}
+
+// NextAllowed returns t, or the start of the first hour after t that falls
+// outside the skipHours and skipDays windows. Both are interpreted in GMT as
+// the RSS specification requires.
+func (sch Schedule) NextAllowed(t time.Time) time.Time {
+	candidate := t
+	// A week of hours covers every combination of skipped hours and days.
+	for i := 0; i < 7*24; i++ {
+		if !sch.skips(candidate.UTC()) {
+			return candidate
+		}
+		candidate = candidate.UTC().Truncate(time.Hour).Add(time.Hour)
+	}
+	return t
+}
+
+func (sch Schedule) skips(t time.Time) bool {
+	for _, hour := range sch.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range sch.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// notBefore reads the earliest time the server wants to be asked again:
+// Retry-After on 429 and 503 responses, otherwise Cache-Control max-age or
+// Expires. It returns the zero time when the response gives no such hint.
+func notBefore(res *http.Response, now time.Time) time.Time {
+	var hint time.Time
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		hint = retryAfter(res.Header.Get("Retry-After"), now)
+	} else {
+		hint = cacheExpiry(res.Header, now)
+	}
+	if hint.IsZero() || !hint.After(now) {
+		return time.Time{}
+	}
+	if hint.Sub(now) > maxNotBefore {
+		return now.Add(maxNotBefore)
+	}
+	return hint
+}
+
+func retryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date
+	}
+	return time.Time{}
+}
+
+func cacheExpiry(header http.Header, now time.Time) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-cache", "no-store":
+			return time.Time{}
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	if date, err := http.ParseTime(header.Get("Expires")); err == nil {
+		return date
+	}
+	return time.Time{}
+}
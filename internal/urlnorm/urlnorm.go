@@ -0,0 +1,107 @@
+package urlnorm
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters added by newsletters, ad networks and
+// social sites that never change which document a URL points to.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+}
+
+// Normalize returns the canonical form of an http or https URL: lower-case
+// scheme and host, no default port, no fragment, no trailing slash on a
+// non-root path and no tracking query parameters. It fails for anything
+// that is not an absolute http or https URL.
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid url %q: scheme must be http or https", rawURL)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "", fmt.Errorf("invalid url %q: missing host", rawURL)
+	}
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	} else if len(u.Path) > 1 && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimRight(u.Path, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.RawPath = ""
+	}
+
+	u.RawQuery = stripTracking(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// Key identifies a normalized URL regardless of scheme, for finding
+// duplicates.
+func Key(normalizedURL string) string {
+	_, rest, found := strings.Cut(normalizedURL, "://")
+	if !found {
+		return normalizedURL
+	}
+	return rest
+}
+
+// stripTracking removes tracking parameters from a raw query, leaving the
+// order and encoding of the others untouched.
+func stripTracking(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var kept []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		name = strings.ToLower(name)
+		if trackingParams[name] || strings.HasPrefix(name, "utm_") {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return strings.Join(kept, "&")
+}
@@ -0,0 +1,70 @@
+package urlnorm
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"already normal", "https://example.com/feed", "https://example.com/feed"},
+		{"upper-case scheme and host", "HTTPS://Example.COM/Feed", "https://example.com/Feed"},
+		{"surrounding space", "  https://example.com/feed\n", "https://example.com/feed"},
+		{"default http port", "http://example.com:80/feed", "http://example.com/feed"},
+		{"default https port", "https://example.com:443/feed", "https://example.com/feed"},
+		{"other port kept", "https://example.com:8443/feed", "https://example.com:8443/feed"},
+		{"http port on https kept", "https://example.com:80/feed", "https://example.com:80/feed"},
+		{"trailing dot in host", "https://example.com./feed", "https://example.com/feed"},
+		{"ipv6 host", "http://[::1]:80/feed", "http://[::1]/feed"},
+		{"ipv6 host with port", "http://[::1]:8080/feed", "http://[::1]:8080/feed"},
+		{"fragment", "https://example.com/feed#top", "https://example.com/feed"},
+		{"empty path", "https://example.com", "https://example.com/"},
+		{"root path", "https://example.com/", "https://example.com/"},
+		{"trailing slash", "https://example.com/feed/", "https://example.com/feed"},
+		{"several trailing slashes", "https://example.com/feed//", "https://example.com/feed"},
+		{"tracking parameters", "https://example.com/a?utm_source=x&id=1&fbclid=y&UTM_Medium=z", "https://example.com/a?id=1"},
+		{"only tracking parameters", "https://example.com/a?utm_source=x", "https://example.com/a"},
+		{"encoded tracking name", "https://example.com/a?%75tm_source=x&b=2", "https://example.com/a?b=2"},
+		{"query order and encoding kept", "https://example.com/a?b=%2F&a=1", "https://example.com/a?b=%2F&a=1"},
+		{"empty query", "https://example.com/a?", "https://example.com/a"},
+		{"escaped path kept", "https://example.com/a%20b", "https://example.com/a%20b"},
+		{"user info kept", "https://user@example.com/a", "https://user@example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if err != nil {
+				t.Fatalf("Normalize(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRejects(t *testing.T) {
+	for _, in := range []string{"", "example.com/feed", "/feed", "ftp://example.com/feed", "mailto:a@example.com", "https:///feed", "http://exa mple.com/"} {
+		if got, err := Normalize(in); err == nil {
+			t.Errorf("Normalize(%q) = %q, want an error", in, got)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://example.com/feed", "example.com/feed"},
+		{"http://example.com/feed", "example.com/feed"},
+		{"example.com/feed", "example.com/feed"},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.in); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
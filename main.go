@@ -5,19 +5,30 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Walther-Knight/blogGATOR/internal/config"
 	"github.com/Walther-Knight/blogGATOR/internal/database"
+	"github.com/Walther-Knight/blogGATOR/internal/download"
+	"github.com/Walther-Knight/blogGATOR/internal/htmltext"
 	"github.com/Walther-Knight/blogGATOR/internal/rss"
+	"github.com/Walther-Knight/blogGATOR/internal/urlnorm"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 type state struct {
-	db *database.Queries
+	db      *database.Queries
+	conn    *sql.DB
+	fetcher *rss.Fetcher
 	*config.Config
 }
 
@@ -47,6 +58,76 @@ func middlewareLoggedIn(handler func(s *state, cmd command, user database.User)
 	}
 }
 
+// findFeed looks a feed up by URL as typed, then by any stored feed URL that
+// only differs from it in form or by http and https, and finally among the
+// URLs feeds moved away from. Stored URLs are kept as they were given, so
+// the last two are matched on their url_key.
+func findFeed(ctx context.Context, q *database.Queries, rawURL string) (database.Feed, error) {
+	feed, err := q.GetFeed(ctx, rawURL)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, err
+	}
+	feed, err = q.GetFeedByAlias(ctx, rawURL)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, err
+	}
+
+	key := urlKey(rawURL)
+	if !key.Valid {
+		return feed, err
+	}
+	feed, err = q.GetFeedByURLKey(ctx, key)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, err
+	}
+	return q.GetFeedByAliasKey(ctx, key)
+}
+
+// urlKey returns the url_key stored with a feed, feed alias or post URL: its
+// normalized form without the scheme. It is NULL for a URL that cannot be
+// normalized.
+func urlKey(rawURL string) sql.NullString {
+	normalized, err := urlnorm.Normalize(rawURL)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: urlnorm.Key(normalized), Valid: true}
+}
+
+// newFetcher builds the shared HTTP fetcher from the "http" section of the
+// config file, which may be absent.
+func newFetcher(httpCfg *config.HTTPConfig) (*rss.Fetcher, error) {
+	if httpCfg == nil {
+		return rss.NewFetcher(rss.FetcherConfig{})
+	}
+
+	var timeout time.Duration
+	if httpCfg.Timeout != "" {
+		parsed, err := time.ParseDuration(httpCfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = parsed
+	}
+
+	hosts := make(map[string]rss.HostConfig, len(httpCfg.Hosts))
+	for host, hostCfg := range httpCfg.Hosts {
+		hosts[host] = rss.HostConfig{
+			Headers: hostCfg.Headers,
+			Cookies: hostCfg.Cookies,
+		}
+	}
+
+	return rss.NewFetcher(rss.FetcherConfig{
+		Timeout:      timeout,
+		MaxBodyBytes: httpCfg.MaxBodyBytes,
+		ProxyURL:     httpCfg.ProxyURL,
+		UserAgent:    httpCfg.UserAgent,
+		CABundle:     httpCfg.CABundle,
+		Hosts:        hosts,
+	})
+}
+
 func handlerLogin(s *state, cmd command) error {
 	if len(cmd.args) == 0 {
 		return fmt.Errorf("invalid command: username required")
@@ -141,24 +222,6 @@ func handlerGetAllUsers(s *state, cmd command) error {
 	return nil
 }
 
-func handlerAgg(s *state, cmd command) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf(("invalid command: syntax agg <timeBetweenReqs>"))
-	}
-
-	timeBetweenReqs, err := time.ParseDuration(cmd.args[0])
-	if err != nil {
-		return fmt.Errorf("invalid command: duration should be valid: %w", err)
-	}
-
-	fmt.Printf("Collecting feeds every %v", timeBetweenReqs)
-	ticker := time.NewTicker(timeBetweenReqs)
-	for ; ; <-ticker.C {
-		scrapeFeeds(s)
-	}
-
-}
-
 func handlerAddFeed(s *state, cmd command, user database.User) error {
 	if len(cmd.args) > 2 {
 		return fmt.Errorf(("invalid command: too many arguments usage 'addfeed <name> <url>'"))
@@ -169,15 +232,37 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 	}
 
 	name := cmd.args[0]
-	url := cmd.args[1]
 
+	// The URL is stored as given; findFeed compares it to the other feeds in
+	// normalized form.
+	pageURL := strings.TrimSpace(cmd.args[1])
+	url, err := discoverFeedURL(context.Background(), s.fetcher, pageURL)
+	if err != nil {
+		return err
+	}
+
+	existing, err := findFeed(context.Background(), s.db, url)
+	if err == nil {
+		return fmt.Errorf("feed already added as %s", existing.Url)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error retrieving feed: %w", err)
+	}
+
+	// The unique url_key also stops a feed added at the same time by another
+	// process.
 	feed, err2 := s.db.CreateFeed(context.Background(), database.CreateFeedParams{
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Name:      name,
 		Url:       url,
 		UserID:    user.ID,
+		UrlKey:    urlKey(url),
 	})
+	var pqErr *pq.Error
+	if errors.As(err2, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("feed %s already added", url)
+	}
 	if err2 != nil {
 		return fmt.Errorf("error creating feed in database: %w", err2)
 	}
@@ -212,6 +297,126 @@ func handlerListFeeds(s *state, cmd command) error {
 	return nil
 }
 
+// deadFeedFailures is the number of consecutive failed fetches after which
+// feedstatus reports a feed as dead rather than failing.
+const deadFeedFailures = 10
+
+func handlerFeedStatus(s *state, cmd command) error {
+	if len(cmd.args) != 0 {
+		return fmt.Errorf(("invalid command: no argument required"))
+	}
+
+	feeds, err := s.db.GetFeedStatus(context.Background())
+	if err != nil {
+		return fmt.Errorf("error retrieving feed status from database: %w", err)
+	}
+
+	for _, feed := range feeds {
+		status := "healthy"
+		if feed.DisabledAt.Valid {
+			status = "disabled"
+		} else if feed.ConsecutiveFailures >= deadFeedFailures {
+			status = "dead"
+		} else if feed.ConsecutiveFailures > 0 {
+			status = "failing"
+		}
+
+		fmt.Printf("[%s] %s (%s)\n", status, feed.Name, feed.Url)
+		if feed.LastSuccessAt.Valid {
+			fmt.Printf(" Last success: %v\n", feed.LastSuccessAt.Time)
+		} else {
+			fmt.Println(" Last success: never")
+		}
+		if feed.ConsecutiveFailures > 0 {
+			fmt.Printf(" Consecutive failures: %d\n", feed.ConsecutiveFailures)
+			fmt.Printf(" Last error: %s\n", feed.LastError.String)
+		}
+		if feed.BackoffUntil.Valid {
+			fmt.Printf(" Backing off until: %v\n", feed.BackoffUntil.Time)
+		}
+		if feed.DisabledAt.Valid {
+			fmt.Printf(" Disabled at: %v\n", feed.DisabledAt.Time)
+		}
+		if feed.LastSkippedItems > 0 {
+			fmt.Printf(" Malformed items skipped in last fetch: %d\n", feed.LastSkippedItems)
+		}
+		if feed.NextFetchAt.Valid {
+			fmt.Printf(" Next fetch: %v\n", feed.NextFetchAt.Time)
+		}
+	}
+
+	return nil
+}
+
+func handlerEnableFeed(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf(("invalid command: usage 'enablefeed <url>'"))
+	}
+
+	url := cmd.args[0]
+
+	currentFeed, err := findFeed(context.Background(), s.db, url)
+	if err != nil {
+		return fmt.Errorf("error retrieving feed: %w", err)
+	}
+
+	err2 := s.db.EnableFeed(context.Background(), currentFeed.ID)
+	if err2 != nil {
+		return fmt.Errorf("error enabling feed: %w", err2)
+	}
+
+	fmt.Printf("Feed %s enabled\n", currentFeed.Name)
+	return nil
+}
+
+func handlerSetInterval(s *state, cmd command) error {
+	if len(cmd.args) < 2 || len(cmd.args) > 3 {
+		return fmt.Errorf(("invalid command: usage 'setinterval <url> <duration> [adaptive]'"))
+	}
+
+	url := cmd.args[0]
+
+	interval, err := time.ParseDuration(cmd.args[1])
+	if err != nil {
+		return fmt.Errorf("invalid command: duration should be valid: %w", err)
+	}
+	if interval < time.Second {
+		return fmt.Errorf("invalid command: duration should be at least 1s")
+	}
+
+	adaptive := false
+	if len(cmd.args) == 3 {
+		if cmd.args[2] != "adaptive" {
+			return fmt.Errorf("invalid command: usage 'setinterval <url> <duration> [adaptive]'")
+		}
+		adaptive = true
+	}
+
+	currentFeed, err2 := findFeed(context.Background(), s.db, url)
+	if err2 != nil {
+		return fmt.Errorf("error retrieving feed: %w", err2)
+	}
+
+	err3 := s.db.SetFeedSchedule(context.Background(), database.SetFeedScheduleParams{
+		FetchIntervalSeconds: sql.NullInt32{
+			Int32: int32(interval / time.Second),
+			Valid: true,
+		},
+		AdaptiveSchedule: adaptive,
+		ID:               currentFeed.ID,
+	})
+	if err3 != nil {
+		return fmt.Errorf("error setting feed interval: %w", err3)
+	}
+
+	if adaptive {
+		fmt.Printf("Feed %s fetched adaptively, at most every %v\n", currentFeed.Name, interval)
+	} else {
+		fmt.Printf("Feed %s fetched every %v\n", currentFeed.Name, interval)
+	}
+	return nil
+}
+
 func handlerFollow(s *state, cmd command, user database.User) error {
 	if len(cmd.args) == 0 {
 		return fmt.Errorf(("invalid command: url required"))
@@ -219,7 +424,18 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 
 	url := cmd.args[0]
 
-	currentFeed, err2 := s.db.GetFeed(context.Background(), url)
+	currentFeed, err2 := findFeed(context.Background(), s.db, url)
+	if errors.Is(err2, sql.ErrNoRows) {
+		// Not a stored feed URL; it may be the site the feed belongs to.
+		feedURL, err := discoverFeedURL(context.Background(), s.fetcher, url)
+		if err != nil {
+			return err
+		}
+		currentFeed, err2 = findFeed(context.Background(), s.db, feedURL)
+		if errors.Is(err2, sql.ErrNoRows) {
+			return fmt.Errorf("feed %s has not been added, use 'addfeed <name> %s'", feedURL, feedURL)
+		}
+	}
 	if err2 != nil {
 		return fmt.Errorf("error retrieving feed: %w", err2)
 	}
@@ -248,7 +464,7 @@ func handlerUnFollow(s *state, cmd command, user database.User) error {
 
 	url := cmd.args[0]
 
-	currentFeed, err := s.db.GetFeed(context.Background(), url)
+	currentFeed, err := findFeed(context.Background(), s.db, url)
 	if err != nil {
 		return fmt.Errorf("error retrieving feed: %w", err)
 	}
@@ -275,18 +491,24 @@ func handlerFollowing(s *state, cmd command, user database.User) error {
 	}
 
 	for _, feed := range followingRes {
-		fmt.Println(feed.Name)
+		fmt.Printf("%s (%d unread)\n", feed.Name, feed.UnreadCount)
 	}
 
 	return nil
 }
 
 func handlerBrowse(s *state, cmd command, user database.User) error {
-	var limit string
-	if len(cmd.args) == 0 {
-		limit = "2"
-	} else {
-		limit = cmd.args[0]
+	limit := "2"
+	unreadOnly := true
+	for _, arg := range cmd.args {
+		switch arg {
+		case "--unread":
+			unreadOnly = true
+		case "--all":
+			unreadOnly = false
+		default:
+			limit = arg
+		}
 	}
 	parsedLimit, err := strconv.ParseInt(limit, 10, 32)
 	if err != nil {
@@ -295,77 +517,193 @@ func handlerBrowse(s *state, cmd command, user database.User) error {
 	convLimit := int32(parsedLimit)
 
 	browseRes, err2 := s.db.GetPostsByUser(context.Background(), database.GetPostsByUserParams{
-		UserID: user.ID,
-		Limit:  convLimit,
+		UserID:     user.ID,
+		UnreadOnly: unreadOnly,
+		Limit:      convLimit,
 	})
 	if err2 != nil {
 		return fmt.Errorf("error retrieving posts by user from database: %w", err2)
 	}
 
 	for i, post := range browseRes {
-		fmt.Printf("Post Number %d\n", i+1)
-		fmt.Println(post.Title)
-		fmt.Println(post.Description)
+		fmt.Printf("Post Number %d (ID %d)\n", i+1, post.ID)
+		fmt.Println(htmltext.StripControls(post.Title))
+		if post.Author.Valid {
+			fmt.Printf("By %s\n", htmltext.StripControls(post.Author.String))
+		}
+		// Posts stored before descriptions were rendered are rendered here.
+		if post.DescriptionText.Valid {
+			fmt.Println(htmltext.StripControls(post.DescriptionText.String))
+		} else {
+			fmt.Println(htmltext.Render(post.Description.String, postTextWidth))
+		}
 		fmt.Println(post.PublishedAt)
-		fmt.Println(post.Url)
+		fmt.Println(htmltext.StripControls(post.Url))
+
+		categories, err3 := s.db.GetPostCategories(context.Background(), post.ID)
+		if err3 != nil {
+			return fmt.Errorf("error retrieving post categories from database: %w", err3)
+		}
+		if len(categories) > 0 {
+			fmt.Printf("Categories: %s\n", htmltext.StripControls(strings.Join(categories, ", ")))
+		}
+
+		if post.CommentsUrl.Valid {
+			fmt.Printf("Comments: %s\n", htmltext.StripControls(post.CommentsUrl.String))
+		}
+
+		enclosures, err4 := s.db.GetPostEnclosures(context.Background(), post.ID)
+		if err4 != nil {
+			return fmt.Errorf("error retrieving post enclosures from database: %w", err4)
+		}
+		for _, enclosure := range enclosures {
+			fmt.Printf("Enclosure: %s (%s, %d bytes)\n", htmltext.StripControls(enclosure.Url), htmltext.StripControls(enclosure.MimeType.String), enclosure.Length.Int64)
+		}
 	}
 
 	return nil
 }
 
-func scrapeFeeds(s *state) error {
-	nextFeed, err := s.db.GetNextFeedToFetch(context.Background())
+func handlerRevisions(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf(("invalid command: usage 'revisions <post-id>'"))
+	}
+
+	postID, err := strconv.ParseInt(cmd.args[0], 10, 32)
 	if err != nil {
-		return fmt.Errorf("error fetching next feed: %w", err)
+		return fmt.Errorf("error converting post id argument to integer: %w", err)
 	}
 
-	err2 := s.db.MarkFeed(context.Background(), database.MarkFeedParams{
-		LastFetchedAt: sql.NullTime{
-			Time:  time.Now(),
-			Valid: true,
-		},
-		ID: nextFeed.ID,
-	})
+	revisions, err2 := s.db.GetPostRevisions(context.Background(), int32(postID))
 	if err2 != nil {
-		return fmt.Errorf("error marking feed fetched: %w", err2)
+		return fmt.Errorf("error retrieving post revisions from database: %w", err2)
 	}
 
-	currentFeed, err := rss.FetchFeed(context.Background(), nextFeed.Url)
+	if len(revisions) == 0 {
+		fmt.Println("No revisions recorded for this post")
+		return nil
+	}
+
+	for _, revision := range revisions {
+		fmt.Printf("Replaced at %v\n", revision.RevisedAt)
+		fmt.Printf(" Title: %s\n", htmltext.StripControls(revision.Title))
+		fmt.Printf(" Published: %v\n", revision.PublishedAt)
+		if revision.Author.Valid {
+			fmt.Printf(" Author: %s\n", htmltext.StripControls(revision.Author.String))
+		}
+		fmt.Printf(" Description: %s\n", htmltext.Render(revision.Description.String, 0))
+		if revision.Content.Valid {
+			fmt.Printf(" Content: %s\n", htmltext.Render(revision.Content.String, 0))
+		}
+	}
+
+	return nil
+}
+
+func handlerEpisodes(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf(("invalid command: usage 'episodes <url>'"))
+	}
+
+	currentFeed, err := findFeed(context.Background(), s.db, cmd.args[0])
 	if err != nil {
-		return fmt.Errorf("error fetching feed: %w", err)
+		return fmt.Errorf("error retrieving feed: %w", err)
+	}
+
+	episodes, err2 := s.db.GetFeedEpisodes(context.Background(), currentFeed.ID)
+	if err2 != nil {
+		return fmt.Errorf("error retrieving episodes from database: %w", err2)
+	}
+
+	if len(episodes) == 0 {
+		fmt.Printf("No episodes found for %s\n", currentFeed.Name)
+		return nil
 	}
 
-	for _, item := range currentFeed.Channel.Item {
-		parsedDate, err2 := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", item.PubDate)
-		if err2 != nil {
-			fmt.Printf("error parsing blog time: %v\n", err2)
-			continue
+	for _, episode := range episodes {
+		var number string
+		if episode.Season.Valid {
+			number += fmt.Sprintf("S%d", episode.Season.Int32)
 		}
-		err3 := s.db.CreatePost(context.Background(), database.CreatePostParams{
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-			Title:     item.Title,
-			Url:       item.Link,
-			Description: sql.NullString{
-				String: item.Description,
-				Valid:  item.Description != "",
-			},
-			PublishedAt: parsedDate,
-			FeedID:      nextFeed.ID,
-		})
-		if err3 != nil {
-			if pqErr, ok := err3.(*pq.Error); ok {
-				if pqErr.Code == "23505" {
-					continue
-				}
-			}
-			fmt.Printf("error inserting to posts table: %v\n", err3)
+		if episode.Episode.Valid {
+			number += fmt.Sprintf("E%d", episode.Episode.Int32)
+		}
+		fmt.Printf("%d: %s %s\n", episode.ID, number, htmltext.StripControls(episode.Title))
+		fmt.Printf(" Published: %v\n", episode.PublishedAt)
+		if episode.DurationSeconds.Valid {
+			fmt.Printf(" Duration: %v\n", time.Duration(episode.DurationSeconds.Int32)*time.Second)
+		}
+		if episode.DownloadPath.Valid {
+			fmt.Printf(" Downloaded: %s\n", episode.DownloadPath.String)
 		}
 	}
 
 	return nil
 }
 
+func handlerDownload(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf(("invalid command: usage 'download <post-id>'"))
+	}
+
+	postID, err := strconv.ParseInt(cmd.args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("error converting post id argument to integer: %w", err)
+	}
+
+	enclosures, err2 := s.db.GetPostEnclosures(context.Background(), int32(postID))
+	if err2 != nil {
+		return fmt.Errorf("error retrieving post enclosures from database: %w", err2)
+	}
+	if len(enclosures) == 0 {
+		return fmt.Errorf("post %d has no enclosure to download", postID)
+	}
+	enclosureURL := enclosures[0].Url
+
+	downloadDir, err3 := s.Downloads()
+	if err3 != nil {
+		return err3
+	}
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return fmt.Errorf("error creating download directory: %w", err)
+	}
+
+	fileName := "episode"
+	if parsedURL, err := url.Parse(enclosureURL); err == nil {
+		if base := path.Base(parsedURL.Path); base != "/" && base != "." {
+			fileName = base
+		}
+	}
+	filePath := filepath.Join(downloadDir, fmt.Sprintf("%d-%s", postID, fileName))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Downloading %s to %s\n", enclosureURL, filePath)
+	result, err4 := download.File(ctx, s.fetcher.DownloadClient(), enclosureURL, filePath)
+	if err4 != nil {
+		return fmt.Errorf("error downloading enclosure (run download again to resume): %w", err4)
+	}
+
+	err5 := s.db.SavePostDownload(context.Background(), database.SavePostDownloadParams{
+		PostID:       int32(postID),
+		Url:          enclosureURL,
+		Path:         result.Path,
+		Bytes:        result.Bytes,
+		Sha256:       result.SHA256,
+		DownloadedAt: time.Now(),
+	})
+	if err5 != nil {
+		return fmt.Errorf("error recording download: %w", err5)
+	}
+
+	if result.Resumed {
+		fmt.Println("Resumed partial download")
+	}
+	fmt.Printf("Saved %d bytes, sha256 %s\n", result.Bytes, result.SHA256)
+	return nil
+}
+
 func (c *commands) run(s *state, cmd command) error {
 	handler, exists := c.cmds[cmd.name]
 	if !exists {
@@ -397,6 +735,14 @@ func main() {
 	}
 	dbQueries := database.New(db)
 	appState.db = dbQueries
+	appState.conn = db
+
+	fetcher, err := newFetcher(cfg.HTTP)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in http config: %v\n", err)
+		os.Exit(1)
+	}
+	appState.fetcher = fetcher
 
 	cmds.register("login", handlerLogin)
 	cmds.register("register", handlerRegister)
@@ -405,10 +751,19 @@ func main() {
 	cmds.register("agg", handlerAgg)
 	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed))
 	cmds.register("feeds", handlerListFeeds)
+	cmds.register("feedstatus", handlerFeedStatus)
+	cmds.register("dedupefeeds", handlerDedupeFeeds)
+	cmds.register("enablefeed", handlerEnableFeed)
+	cmds.register("setinterval", handlerSetInterval)
 	cmds.register("follow", middlewareLoggedIn(handlerFollow))
 	cmds.register("following", middlewareLoggedIn(handlerFollowing))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnFollow))
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
+	cmds.register("read", middlewareLoggedIn(handlerRead))
+	cmds.register("markread", middlewareLoggedIn(handlerMarkRead))
+	cmds.register("revisions", handlerRevisions)
+	cmds.register("episodes", handlerEpisodes)
+	cmds.register("download", handlerDownload)
 
 	args := os.Args
 	if len(args) < 2 {
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Walther-Knight/blogGATOR/internal/database"
+	"github.com/Walther-Knight/blogGATOR/internal/htmltext"
+)
+
+// handlerRead prints a post in full and marks it read for the current user.
+func handlerRead(s *state, cmd command, user database.User) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf(("invalid command: usage 'read <post-id>'"))
+	}
+
+	postID, err := strconv.ParseInt(cmd.args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("error converting post id argument to integer: %w", err)
+	}
+
+	post, err2 := s.db.GetPost(context.Background(), int32(postID))
+	if errors.Is(err2, sql.ErrNoRows) {
+		return fmt.Errorf("post %d not found", postID)
+	}
+	if err2 != nil {
+		return fmt.Errorf("error retrieving post from database: %w", err2)
+	}
+
+	fmt.Println(htmltext.StripControls(post.Title))
+	if post.Author.Valid {
+		fmt.Printf("By %s\n", htmltext.StripControls(post.Author.String))
+	}
+	fmt.Println(post.PublishedAt)
+	fmt.Println(htmltext.StripControls(post.Url))
+	fmt.Println()
+	// The full content is shown when the feed has it, otherwise the
+	// description.
+	switch {
+	case post.Content.Valid && post.Content.String != "":
+		fmt.Println(htmltext.Render(post.Content.String, postTextWidth))
+	case post.DescriptionText.Valid:
+		fmt.Println(htmltext.StripControls(post.DescriptionText.String))
+	default:
+		fmt.Println(htmltext.Render(post.Description.String, postTextWidth))
+	}
+
+	err3 := s.db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		UserID:    user.ID,
+		PostID:    post.ID,
+		UpdatedAt: time.Now(),
+	})
+	if err3 != nil {
+		return fmt.Errorf("error marking post read: %w", err3)
+	}
+
+	return nil
+}
+
+// handlerMarkRead marks the posts of one followed feed, of every followed
+// feed, or of every followed feed published before a date as read.
+func handlerMarkRead(s *state, cmd command, user database.User) error {
+	params := database.MarkPostsReadParams{
+		UserID:    user.ID,
+		UpdatedAt: time.Now(),
+	}
+	switch {
+	case len(cmd.args) == 1 && cmd.args[0] == "--all":
+	case len(cmd.args) == 2 && cmd.args[0] == "--feed":
+		feed, err := findFeed(context.Background(), s.db, cmd.args[1])
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("feed %s not found", cmd.args[1])
+		}
+		if err != nil {
+			return fmt.Errorf("error retrieving feed: %w", err)
+		}
+		params.FeedID = sql.NullInt32{Int32: feed.ID, Valid: true}
+	case len(cmd.args) == 2 && cmd.args[0] == "--before":
+		before, err := parseDate(cmd.args[1])
+		if err != nil {
+			return err
+		}
+		params.Before = sql.NullTime{Time: before, Valid: true}
+	default:
+		return fmt.Errorf(("invalid command: usage 'markread --feed <url> | --all | --before <date>'"))
+	}
+
+	marked, err2 := s.db.MarkPostsRead(context.Background(), params)
+	if err2 != nil {
+		return fmt.Errorf("error marking posts read: %w", err2)
+	}
+
+	fmt.Printf("Marked %d posts read\n", marked)
+	return nil
+}
+
+// parseDate accepts a date as 2006-01-02 or a full RFC 3339 timestamp. The
+// result is in UTC, like the publish dates it is compared with.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	return date.UTC(), nil
+}
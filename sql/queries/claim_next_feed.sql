@@ -0,0 +1,18 @@
+-- name: GetDatabaseTime :one
+SELECT now()::TIMESTAMP;
+
+-- name: ClaimNextFeed :one
+UPDATE feeds
+SET claimed_until = now() + make_interval(secs => @lease_seconds::INTEGER)
+WHERE id = (
+    SELECT id
+    FROM feeds
+    WHERE (next_fetch_at IS NULL OR next_fetch_at <= @due_before::TIMESTAMP)
+    AND (claimed_until IS NULL OR claimed_until < now())
+    AND (backoff_until IS NULL OR backoff_until <= @due_before::TIMESTAMP)
+    AND disabled_at IS NULL
+    ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, last_fetched_at, url, etag, last_modified, fetch_interval_seconds, adaptive_schedule, ttl_minutes, skip_hours, skip_days;
\ No newline at end of file
@@ -1,10 +1,11 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (created_at, updated_at, name, url, user_id, url_key)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
 RETURNING *;
\ No newline at end of file
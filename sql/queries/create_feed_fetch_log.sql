@@ -0,0 +1,13 @@
+-- name: CreateFeedFetchLog :exec
+INSERT INTO feed_fetch_log (feed_id, fetched_at, status_code, duration_ms, bytes, item_count, new_posts, error_text, skipped_items)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+);
\ No newline at end of file
@@ -1,12 +0,0 @@
--- name: CreatePost :exec
-INSERT INTO posts (created_at, updated_at, title, url, description, published_at, feed_id)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7
-)
-RETURNING *;
\ No newline at end of file
@@ -0,0 +1,51 @@
+-- name: GetFeedURLs :many
+SELECT id, url
+FROM feeds
+ORDER BY id;
+
+-- name: MergeFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = @keep_id::INTEGER
+WHERE feed_id = @duplicate_id::INTEGER
+AND user_id NOT IN (
+    SELECT user_id
+    FROM feed_follows
+    WHERE feed_id = @keep_id::INTEGER
+);
+
+-- name: DeleteFeedFollows :exec
+DELETE FROM feed_follows
+WHERE feed_id = $1;
+
+-- name: MergeFeedPosts :exec
+UPDATE posts
+SET feed_id = @keep_id::INTEGER
+WHERE feed_id = @duplicate_id::INTEGER
+AND guid NOT IN (
+    SELECT guid
+    FROM posts
+    WHERE feed_id = @keep_id::INTEGER
+);
+
+-- name: DeleteFeedPosts :exec
+DELETE FROM posts
+WHERE feed_id = $1;
+
+-- name: MergeFeedFetchLog :exec
+UPDATE feed_fetch_log
+SET feed_id = @keep_id::INTEGER
+WHERE feed_id = @duplicate_id::INTEGER;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: SetFeedURL :exec
+UPDATE feeds
+SET url = $2, url_key = $3, updated_at = $4
+WHERE id = $1;
+
+-- name: SetFeedURLKey :exec
+UPDATE feeds
+SET url_key = $2
+WHERE id = $1;
\ No newline at end of file
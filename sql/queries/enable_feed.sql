@@ -0,0 +1,4 @@
+-- name: EnableFeed :exec
+UPDATE feeds
+SET disabled_at = NULL, consecutive_failures = 0, failing_since = NULL, backoff_until = NULL
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,47 @@
+-- name: CreateFeedAlias :exec
+INSERT INTO feed_aliases (url, feed_id, created_at, url_key)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (url) DO UPDATE
+SET feed_id = EXCLUDED.feed_id,
+    created_at = EXCLUDED.created_at,
+    url_key = EXCLUDED.url_key;
+
+-- name: DeleteFeedAlias :exec
+DELETE FROM feed_aliases
+WHERE url = $1;
+
+-- name: GetFeedByAlias :one
+SELECT feeds.*
+FROM feeds
+INNER JOIN feed_aliases
+ON feeds.id = feed_aliases.feed_id
+WHERE feed_aliases.url = $1;
+
+-- name: GetFeedByAliasKey :one
+SELECT feeds.*
+FROM feeds
+INNER JOIN feed_aliases
+ON feeds.id = feed_aliases.feed_id
+WHERE feed_aliases.url_key = $1
+ORDER BY feed_aliases.created_at DESC
+LIMIT 1;
+
+-- name: MergeFeedAliases :exec
+UPDATE feed_aliases
+SET feed_id = @keep_id::INTEGER
+WHERE feed_id = @duplicate_id::INTEGER;
+
+-- name: GetFeedAliasURLs :many
+SELECT url
+FROM feed_aliases
+ORDER BY created_at;
+
+-- name: SetFeedAliasURLKey :exec
+UPDATE feed_aliases
+SET url_key = $2
+WHERE url = $1;
\ No newline at end of file
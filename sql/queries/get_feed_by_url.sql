@@ -1,4 +1,9 @@
 -- name: GetFeed :one
 SELECT *
 FROM feeds
-WHERE url = $1;
\ No newline at end of file
+WHERE url = $1;
+
+-- name: GetFeedByURLKey :one
+SELECT *
+FROM feeds
+WHERE url_key = $1;
\ No newline at end of file
@@ -1,5 +1,16 @@
 -- name: GetFeedFollowsForUser :many
-SELECT feeds.name, users.name
+SELECT feeds.name, users.name, (
+    SELECT COUNT(*)
+    FROM posts
+    WHERE posts.feed_id = feeds.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_post_state
+        WHERE user_post_state.user_id = feed_follows.user_id
+        AND user_post_state.post_id = posts.id
+        AND user_post_state.read
+    )
+) AS unread_count
 FROM feed_follows
 INNER JOIN feeds
 ON feed_follows.feed_id = feeds.id
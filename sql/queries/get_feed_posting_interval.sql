@@ -0,0 +1,9 @@
+-- name: GetFeedPostingInterval :one
+SELECT COALESCE(EXTRACT(EPOCH FROM (MAX(published_at) - MIN(published_at))) / NULLIF(COUNT(*) - 1, 0), 0)::BIGINT AS average_gap_seconds
+FROM (
+    SELECT published_at
+    FROM posts
+    WHERE feed_id = $1
+    ORDER BY published_at DESC
+    LIMIT 20
+) AS recent_posts;
\ No newline at end of file
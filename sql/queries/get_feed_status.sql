@@ -0,0 +1,10 @@
+-- name: GetFeedStatus :many
+SELECT name, url, consecutive_failures, last_error, last_fetched_at, last_success_at, backoff_until, disabled_at, next_fetch_at, COALESCE((
+    SELECT skipped_items
+    FROM feed_fetch_log
+    WHERE feed_fetch_log.feed_id = feeds.id
+    ORDER BY fetched_at DESC
+    LIMIT 1
+), 0)::INTEGER AS last_skipped_items
+FROM feeds
+ORDER BY consecutive_failures DESC, name;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetPostRevisions :many
+SELECT revised_at, title, description, published_at, content, author
+FROM post_revisions
+WHERE post_id = $1
+ORDER BY revised_at;
\ No newline at end of file
@@ -3,10 +3,16 @@ SELECT *
 FROM posts
 INNER JOIN feed_follows
 ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.feed_id IN (
-    SELECT feed_id
-    FROM feed_follows
-    WHERE feed_follows.user_id = $1
+WHERE feed_follows.user_id = @user_id
+AND (
+    NOT @unread_only::BOOLEAN
+    OR NOT EXISTS (
+        SELECT 1
+        FROM user_post_state
+        WHERE user_post_state.user_id = @user_id
+        AND user_post_state.post_id = posts.id
+        AND user_post_state.read
+    )
 )
 ORDER BY published_at DESC
-LIMIT $2;
\ No newline at end of file
+LIMIT sqlc.arg('limit');
\ No newline at end of file
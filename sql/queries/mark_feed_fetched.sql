@@ -1,4 +1,4 @@
 -- name: MarkFeed :exec
 UPDATE feeds
-SET last_fetched_at = $1
-WHERE id = $2;
\ No newline at end of file
+SET last_fetched_at = @last_fetched_at, next_fetch_at = now() + make_interval(secs => @next_fetch_seconds::INTEGER), claimed_until = NULL
+WHERE id = @id;
\ No newline at end of file
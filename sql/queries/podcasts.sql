@@ -0,0 +1,29 @@
+-- name: GetFeedEpisodes :many
+SELECT posts.id, posts.title, posts.published_at, posts.duration_seconds, posts.episode, posts.season, post_downloads.path AS download_path
+FROM posts
+LEFT JOIN post_downloads
+ON posts.id = post_downloads.post_id
+WHERE posts.feed_id = $1
+AND EXISTS (
+    SELECT 1
+    FROM post_enclosures
+    WHERE post_enclosures.post_id = posts.id
+)
+ORDER BY posts.published_at DESC;
+
+-- name: SavePostDownload :exec
+INSERT INTO post_downloads (post_id, url, path, bytes, sha256, downloaded_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (post_id) DO UPDATE
+SET url = EXCLUDED.url,
+    path = EXCLUDED.path,
+    bytes = EXCLUDED.bytes,
+    sha256 = EXCLUDED.sha256,
+    downloaded_at = EXCLUDED.downloaded_at;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- name: CreatePostCategories :exec
+INSERT INTO post_categories (post_id, name)
+SELECT posts.id, items.name
+FROM unnest(
+    @guids::TEXT[],
+    @names::TEXT[]
+) AS items(guid, name)
+INNER JOIN posts
+ON posts.guid = items.guid
+AND posts.feed_id = @feed_id::INTEGER
+ON CONFLICT DO NOTHING;
+
+-- name: CreatePostEnclosures :exec
+INSERT INTO post_enclosures (post_id, url, mime_type, length)
+SELECT posts.id, items.url, NULLIF(items.mime_type, ''), NULLIF(items.length, 0)
+FROM unnest(
+    @guids::TEXT[],
+    @urls::TEXT[],
+    @mime_types::TEXT[],
+    @lengths::BIGINT[]
+) AS items(guid, url, mime_type, length)
+INNER JOIN posts
+ON posts.guid = items.guid
+AND posts.feed_id = @feed_id::INTEGER
+ON CONFLICT DO NOTHING;
+
+-- name: GetPostCategories :many
+SELECT name
+FROM post_categories
+WHERE post_id = $1
+ORDER BY name;
+
+-- name: GetPostEnclosures :many
+SELECT url, mime_type, length
+FROM post_enclosures
+WHERE post_id = $1
+ORDER BY id;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET consecutive_failures = 0, last_error = NULL, last_success_at = $1, failing_since = NULL, backoff_until = NULL
+WHERE id = $2;
+
+-- name: RecordFeedFailure :one
+UPDATE feeds
+SET consecutive_failures = consecutive_failures + 1, last_error = @last_error, failing_since = COALESCE(failing_since, @failed_at)
+WHERE id = @id
+RETURNING consecutive_failures, failing_since;
+
+-- name: SetFeedBackoff :exec
+UPDATE feeds
+SET backoff_until = now() + make_interval(secs => @backoff_seconds::INTEGER)
+WHERE id = @id;
+
+-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = $1, backoff_until = NULL
+WHERE id = $2;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: ReleaseFeed :exec
+UPDATE feeds
+SET claimed_until = NULL
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: SetFeedPublisherSchedule :exec
+UPDATE feeds
+SET ttl_minutes = $1, skip_hours = $2, skip_days = $3
+WHERE id = $4;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: SetFeedSchedule :exec
+UPDATE feeds
+SET fetch_interval_seconds = $1, adaptive_schedule = $2,
+    next_fetch_at = GREATEST(next_fetch_at, last_fetched_at + make_interval(secs => $1::INTEGER))
+WHERE id = $3;
\ No newline at end of file
@@ -0,0 +1,83 @@
+-- name: ReplaceBackfilledGuids :exec
+UPDATE posts
+SET guid = items.guid, guid_backfilled = false
+FROM unnest(
+    @guids::TEXT[],
+    @urls::TEXT[],
+    @url_keys::TEXT[]
+) AS items(guid, url, url_key)
+WHERE posts.feed_id = @feed_id::INTEGER
+AND posts.guid_backfilled
+AND (posts.url_key = NULLIF(items.url_key, '') OR posts.url = items.url)
+AND NOT EXISTS (
+    SELECT 1
+    FROM posts AS existing
+    WHERE existing.feed_id = posts.feed_id
+    AND existing.guid = items.guid
+);
+
+-- name: RecordPostRevisions :execrows
+INSERT INTO post_revisions (post_id, revised_at, title, description, published_at, content_hash, content, author)
+SELECT posts.id, @revised_at::TIMESTAMP, posts.title, posts.description, posts.published_at, posts.content_hash, posts.content, posts.author
+FROM posts
+INNER JOIN unnest(
+    @guids::TEXT[],
+    @published_ats::TIMESTAMP[],
+    @published_at_sources::TEXT[],
+    @content_hashes::TEXT[],
+    @contents::TEXT[],
+    @authors::TEXT[]
+) AS items(guid, published_at, published_at_source, content_hash, content, author)
+ON posts.guid = items.guid
+WHERE posts.feed_id = @feed_id::INTEGER
+AND (
+    posts.content_hash IS DISTINCT FROM items.content_hash
+    OR (items.published_at_source <> 'fetch_time' AND posts.published_at_utc AND posts.published_at <> items.published_at)
+    OR (posts.content IS NOT NULL AND posts.content IS DISTINCT FROM NULLIF(items.content, ''))
+    OR (posts.author IS NOT NULL AND posts.author IS DISTINCT FROM NULLIF(items.author, ''))
+);
+
+-- name: UpsertPosts :many
+INSERT INTO posts (created_at, updated_at, title, url, description, published_at, feed_id, published_at_source, content_hash, guid, content, author, comments_url, duration_seconds, episode, season, image_url, description_text, url_key)
+SELECT @fetched_at::TIMESTAMP, @fetched_at::TIMESTAMP, items.title, items.url, NULLIF(items.description, ''), items.published_at, @feed_id::INTEGER, items.published_at_source, items.content_hash, items.guid, NULLIF(items.content, ''), NULLIF(items.author, ''), NULLIF(items.comments_url, ''), NULLIF(items.duration_seconds, 0), NULLIF(items.episode, 0), NULLIF(items.season, 0), NULLIF(items.image_url, ''), NULLIF(items.description_text, ''), NULLIF(items.url_key, '')
+FROM unnest(
+    @titles::TEXT[],
+    @urls::TEXT[],
+    @descriptions::TEXT[],
+    @published_ats::TIMESTAMP[],
+    @published_at_sources::TEXT[],
+    @content_hashes::TEXT[],
+    @guids::TEXT[],
+    @contents::TEXT[],
+    @authors::TEXT[],
+    @comments_urls::TEXT[],
+    @duration_seconds::INTEGER[],
+    @episodes::INTEGER[],
+    @seasons::INTEGER[],
+    @image_urls::TEXT[],
+    @description_texts::TEXT[],
+    @url_keys::TEXT[]
+) AS items(title, url, description, published_at, published_at_source, content_hash, guid, content, author, comments_url, duration_seconds, episode, season, image_url, description_text, url_key)
+ON CONFLICT (feed_id, guid) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    title = EXCLUDED.title,
+    url = EXCLUDED.url,
+    url_key = EXCLUDED.url_key,
+    description = EXCLUDED.description,
+    description_text = EXCLUDED.description_text,
+    content = EXCLUDED.content,
+    author = EXCLUDED.author,
+    comments_url = EXCLUDED.comments_url,
+    duration_seconds = EXCLUDED.duration_seconds,
+    episode = EXCLUDED.episode,
+    season = EXCLUDED.season,
+    image_url = EXCLUDED.image_url,
+    published_at = CASE WHEN EXCLUDED.published_at_source = 'fetch_time' THEN posts.published_at ELSE EXCLUDED.published_at END,
+    published_at_utc = posts.published_at_utc OR EXCLUDED.published_at_source <> 'fetch_time',
+    content_hash = EXCLUDED.content_hash
+WHERE posts.content_hash IS DISTINCT FROM EXCLUDED.content_hash
+OR (EXCLUDED.published_at_source <> 'fetch_time' AND posts.published_at <> EXCLUDED.published_at)
+OR (posts.url, posts.url_key, posts.description, posts.description_text, posts.content, posts.author, posts.comments_url, posts.duration_seconds, posts.episode, posts.season, posts.image_url)
+    IS DISTINCT FROM
+    (EXCLUDED.url, EXCLUDED.url_key, EXCLUDED.description, EXCLUDED.description_text, EXCLUDED.content, EXCLUDED.author, EXCLUDED.comments_url, EXCLUDED.duration_seconds, EXCLUDED.episode, EXCLUDED.season, EXCLUDED.image_url)
+RETURNING (xmax = 0)::BOOLEAN AS inserted;
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- name: GetPost :one
+SELECT *
+FROM posts
+WHERE id = $1;
+
+-- name: MarkPostRead :exec
+INSERT INTO user_post_state (user_id, post_id, read, updated_at)
+VALUES (
+    $1,
+    $2,
+    true,
+    $3
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, updated_at = EXCLUDED.updated_at;
+
+-- name: MarkPostsRead :execrows
+INSERT INTO user_post_state (user_id, post_id, read, updated_at)
+SELECT @user_id::UUID, posts.id, true, @updated_at::TIMESTAMP
+FROM posts
+INNER JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = @user_id::UUID
+AND (sqlc.narg('feed_id')::INTEGER IS NULL OR posts.feed_id = sqlc.narg('feed_id')::INTEGER)
+AND (sqlc.narg('before')::TIMESTAMP IS NULL OR posts.published_at < sqlc.narg('before')::TIMESTAMP)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, updated_at = EXCLUDED.updated_at
+WHERE NOT user_post_state.read;
\ No newline at end of file
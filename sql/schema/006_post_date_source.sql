@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts
+ADD published_at_source TEXT NOT NULL DEFAULT 'parsed';
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN published_at_source;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE feed_fetch_log (
+    id SERIAL PRIMARY KEY,
+    feed_id INTEGER NOT NULL,
+    fetched_at TIMESTAMP NOT NULL,
+    status_code INTEGER,
+    duration_ms INTEGER NOT NULL,
+    bytes INTEGER NOT NULL,
+    item_count INTEGER NOT NULL,
+    new_posts INTEGER NOT NULL,
+    error_text TEXT,
+    CONSTRAINT fk_feed_id FOREIGN KEY (feed_id) REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+ALTER TABLE feeds
+ADD consecutive_failures INTEGER NOT NULL DEFAULT 0,
+ADD last_error TEXT,
+ADD last_success_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN consecutive_failures,
+DROP COLUMN last_error,
+DROP COLUMN last_success_at;
+
+DROP TABLE feed_fetch_log;
\ No newline at end of file
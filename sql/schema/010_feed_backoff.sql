@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD failing_since TIMESTAMP,
+ADD backoff_until TIMESTAMP,
+ADD disabled_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN failing_since,
+DROP COLUMN backoff_until,
+DROP COLUMN disabled_at;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD fetch_interval_seconds INTEGER,
+ADD adaptive_schedule BOOLEAN NOT NULL DEFAULT false,
+ADD next_fetch_at TIMESTAMP;
+
+CREATE INDEX feeds_next_fetch_at_idx ON feeds (next_fetch_at);
+
+-- +goose Down
+DROP INDEX feeds_next_fetch_at_idx;
+
+ALTER TABLE feeds
+DROP COLUMN fetch_interval_seconds,
+DROP COLUMN adaptive_schedule,
+DROP COLUMN next_fetch_at;
\ No newline at end of file
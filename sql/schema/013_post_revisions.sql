@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE posts
+DROP CONSTRAINT posts_url_key,
+ADD CONSTRAINT posts_feed_id_url_key UNIQUE (feed_id, url),
+ADD content_hash TEXT;
+
+UPDATE posts
+SET content_hash = encode(sha256(convert_to(title || E'\n' || COALESCE(description, ''), 'UTF8')), 'hex');
+
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL,
+    revised_at TIMESTAMP NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    published_at TIMESTAMP NOT NULL,
+    content_hash TEXT,
+    CONSTRAINT fk_post_id FOREIGN KEY (post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE post_revisions;
+
+ALTER TABLE posts
+DROP COLUMN content_hash,
+DROP CONSTRAINT posts_feed_id_url_key,
+ADD CONSTRAINT posts_url_key UNIQUE (url);
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- +goose Up
+ALTER TABLE posts
+ADD content TEXT,
+ADD author TEXT,
+ADD comments_url TEXT;
+
+CREATE TABLE post_categories (
+    post_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (post_id, name),
+    CONSTRAINT fk_post_id FOREIGN KEY (post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE post_enclosures (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    mime_type TEXT,
+    length BIGINT,
+    CONSTRAINT fk_post_id FOREIGN KEY (post_id) REFERENCES posts(id)
+    ON DELETE CASCADE,
+    UNIQUE (post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
+
+DROP TABLE post_categories;
+
+ALTER TABLE posts
+DROP COLUMN content,
+DROP COLUMN author,
+DROP COLUMN comments_url;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE posts
+ADD duration_seconds INTEGER,
+ADD episode INTEGER,
+ADD season INTEGER,
+ADD image_url TEXT;
+
+CREATE TABLE post_downloads (
+    post_id INTEGER PRIMARY KEY,
+    url TEXT NOT NULL,
+    path TEXT NOT NULL,
+    bytes BIGINT NOT NULL,
+    sha256 TEXT NOT NULL,
+    downloaded_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_post_id FOREIGN KEY (post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE post_downloads;
+
+ALTER TABLE posts
+DROP COLUMN duration_seconds,
+DROP COLUMN episode,
+DROP COLUMN season,
+DROP COLUMN image_url;
\ No newline at end of file
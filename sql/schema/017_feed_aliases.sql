@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE feed_aliases (
+    url TEXT PRIMARY KEY,
+    feed_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_feed_id FOREIGN KEY (feed_id) REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE feed_aliases;
\ No newline at end of file
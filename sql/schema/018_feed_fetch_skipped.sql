@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feed_fetch_log
+ADD skipped_items INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feed_fetch_log
+DROP COLUMN skipped_items;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts
+ADD description_text TEXT;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN description_text;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE user_post_state (
+    user_id UUID NOT NULL,
+    post_id INTEGER NOT NULL,
+    read BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id),
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_post_id FOREIGN KEY (post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE user_post_state;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE posts
+ADD guid_backfilled BOOLEAN NOT NULL DEFAULT false;
+
+-- Posts stored before item GUIDs were kept got their URL as a GUID. They are
+-- matched by URL instead until the feed shows their real GUID.
+UPDATE posts
+SET guid_backfilled = true
+WHERE guid = url;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN guid_backfilled;
\ No newline at end of file
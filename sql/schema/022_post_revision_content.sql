@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE post_revisions
+ADD content TEXT,
+ADD author TEXT;
+
+-- +goose Down
+ALTER TABLE post_revisions
+DROP COLUMN content,
+DROP COLUMN author;
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- +goose Up
+-- url_key is a URL's normalized form without its scheme (see urlnorm.Key),
+-- so feeds and posts are looked up by it while their URLs are kept as given.
+-- Feeds added before it is filled in by dedupefeeds.
+ALTER TABLE feeds
+ADD url_key TEXT;
+
+CREATE UNIQUE INDEX feeds_url_key_idx ON feeds (url_key);
+
+ALTER TABLE feed_aliases
+ADD url_key TEXT;
+
+CREATE INDEX feed_aliases_url_key_idx ON feed_aliases (url_key);
+
+ALTER TABLE posts
+ADD url_key TEXT;
+
+CREATE INDEX posts_feed_id_url_key_idx ON posts (feed_id, url_key);
+
+-- Posts without a GUID of their own used their link as published. They now
+-- use the normalized link, which they take on when next matched by URL.
+UPDATE posts
+SET guid_backfilled = true
+WHERE guid = url;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN url_key;
+
+ALTER TABLE feed_aliases
+DROP COLUMN url_key;
+
+ALTER TABLE feeds
+DROP COLUMN url_key;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Publish dates used to be stored in the wall-clock time of the feed's own
+-- zone and are now stored in UTC. The zone of an existing date is unknown, so
+-- it is corrected on the post's next refresh without recording a revision.
+ALTER TABLE posts
+ADD published_at_utc BOOLEAN NOT NULL DEFAULT true;
+
+UPDATE posts
+SET published_at_utc = false;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN published_at_utc;
\ No newline at end of file